@@ -0,0 +1,212 @@
+package badger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// AccessLogFormat is the output format of an AccessLog
+type AccessLogFormat int
+
+// Access log formats, AccessLogSlog emits slog records, the others write
+// Common or Combined Log Format lines to the AccessLog Writer
+const (
+	AccessLogSlog AccessLogFormat = iota
+	AccessLogCommon
+	AccessLogCombined
+)
+
+const clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLog is a middleware logging every request once it is served
+type AccessLog struct {
+	// Logger receives the records in AccessLogSlog format, defaults to
+	// slog.Default()
+	Logger *slog.Logger
+	// Level of the records, defaults to slog.LevelInfo
+	Level slog.Level
+	// Format of the output, defaults to AccessLogSlog
+	Format AccessLogFormat
+	// Writer receives the lines in Common and Combined formats
+	Writer io.Writer
+	// SampleRate is the fraction of successful requests logged, between 0
+	// and 1, requests answered with 4xx or 5xx are always logged. 0 logs
+	// every request.
+	SampleRate float64
+	// Skip lists route patterns which are never logged, such as "/health"
+	Skip []string
+	// RequestIDHeader is the response header holding the request id when
+	// the RequestID middleware runs inside this one, defaults to
+	// X-Request-ID
+	RequestIDHeader string
+}
+
+// NewAccessLog returns an AccessLog emitting slog records to logger, a nil
+// logger uses slog.Default()
+func NewAccessLog(logger *slog.Logger) *AccessLog {
+	return &AccessLog{Logger: logger}
+}
+
+// Middleware logs the requests served by the given handler
+func (al *AccessLog) Middleware(h http.Handler) http.Handler {
+	skip := make([]string, 0, len(al.Skip))
+
+	for _, pattern := range al.Skip {
+		skip = append(skip, buildRoutePath("", pattern))
+	}
+
+	requestidheader := al.RequestIDHeader
+
+	if requestidheader == "" {
+		requestidheader = DefaultRequestIDHeader
+	}
+
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		pattern, basepath := "", ""
+
+		if route, ok := RouteFromContext(req.Context()); ok {
+			pattern, basepath = route.Pattern, route.BasePath
+		}
+
+		if slices.Contains(skip, pattern) {
+			h.ServeHTTP(res, req)
+			return
+		}
+
+		start := time.Now()
+		rw := trackResponseWriter(res)
+		h.ServeHTTP(rw, req)
+
+		status := rw.Status()
+
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		if status < http.StatusBadRequest && al.SampleRate > 0 && rand.Float64() >= al.SampleRate {
+			return
+		}
+
+		requestid := RequestIDFromContext(req.Context())
+
+		// RequestID middlewares wrapped by this one only set the header
+		if requestid == "" {
+			requestid = rw.Header().Get(requestidheader)
+		}
+
+		entry := accessLogEntry{req, start, time.Since(start), pattern, basepath, status, rw.Written(), requestid}
+		al.write(req.Context(), entry)
+	})
+}
+
+type accessLogEntry struct {
+	req       *http.Request
+	start     time.Time
+	duration  time.Duration
+	pattern   string
+	basepath  string
+	status    int
+	written   int64
+	requestid string
+}
+
+func (al *AccessLog) write(ctx context.Context, e accessLogEntry) {
+	switch al.Format {
+	case AccessLogCommon, AccessLogCombined:
+		if al.Writer == nil {
+			return
+		}
+
+		line := fmt.Sprintf(
+			"%s - %s [%s] \"%s %s %s\" %d %s",
+			remoteIP(e.req),
+			orDash(basicAuthUser(e.req)),
+			e.start.Format(clfTimeFormat),
+			GetOriginalMethodFromRequest(e.req),
+			requestURI(e.req),
+			e.req.Proto,
+			e.status,
+			orDash(bytesOrEmpty(e.written)),
+		)
+
+		if al.Format == AccessLogCombined {
+			line += fmt.Sprintf(" %q %q", orDash(e.req.Referer()), orDash(e.req.UserAgent()))
+		}
+
+		fmt.Fprintln(al.Writer, line)
+	default:
+		logger := al.Logger
+
+		if logger == nil {
+			logger = slog.Default()
+		}
+
+		method := GetOriginalMethodFromRequest(e.req)
+		attrs := []slog.Attr{
+			slog.String("method", method),
+			slog.String("path", getOriginalPathFromRequest(e.req)),
+			slog.String("route", e.pattern),
+			slog.String("base_path", e.basepath),
+			slog.Int("status", e.status),
+			slog.Int64("bytes", e.written),
+			slog.Duration("duration", e.duration),
+			slog.String("remote_ip", remoteIP(e.req)),
+			slog.String("user_agent", e.req.UserAgent()),
+			slog.String("request_id", e.requestid),
+		}
+
+		if e.req.Method != method {
+			attrs = append(attrs, slog.String("method_override", e.req.Method))
+		}
+
+		logger.LogAttrs(ctx, al.Level, "request", attrs...)
+	}
+}
+
+// requestURI returns the request target as sent by the client, before the
+// Mux normalised its path
+func requestURI(req *http.Request) string {
+	u := *req.URL
+	u.Path = getOriginalPathFromRequest(req)
+	u.RawPath = ""
+
+	return u.RequestURI()
+}
+
+func remoteIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+
+	if err != nil {
+		return req.RemoteAddr
+	}
+
+	return host
+}
+
+func basicAuthUser(req *http.Request) string {
+	user, _, _ := req.BasicAuth()
+	return user
+}
+
+func bytesOrEmpty(written int64) string {
+	if written == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%d", written)
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
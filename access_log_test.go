@@ -0,0 +1,179 @@
+package badger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+func TestAccessLogSlog(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get("users/:id", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusCreated)
+		fmt.Fprint(res, "hello")
+	}))
+	router.Use(badger.NewAccessLog(slog.New(slog.NewJSONHandler(buffer, nil))).Middleware)
+	router.Use(badger.NewRequestID().Middleware)
+
+	req, _ := http.NewRequest(GET, "/v1/users/42", nil)
+	req.RemoteAddr = "10.0.0.1:5000"
+	req.Header.Set("User-Agent", "tester")
+	req.Header.Set(badger.DefaultRequestIDHeader, "abc-123")
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+
+	record := map[string]interface{}{}
+
+	if err := json.Unmarshal(buffer.Bytes(), &record); err != nil {
+		t.Fatalf("Test failed, invalid record '%s'.", buffer.String())
+	}
+
+	expected := map[string]interface{}{
+		"method":     "GET",
+		"path":       "/v1/users/42",
+		"route":      "/v1/users/:id/",
+		"base_path":  "/v1/",
+		"status":     float64(http.StatusCreated),
+		"bytes":      float64(5),
+		"remote_ip":  "10.0.0.1",
+		"user_agent": "tester",
+		"request_id": "abc-123",
+	}
+
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("Test failed, expected '%s' to be '%v' got '%v'.", key, value, record[key])
+		}
+	}
+
+	if _, ok := record["duration"]; !ok {
+		t.Error("Test failed, expected duration to be logged.")
+	}
+}
+
+func TestAccessLogSkip(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	al := badger.NewAccessLog(slog.New(slog.NewJSONHandler(buffer, nil)))
+	al.Skip = []string{"/v1/health"}
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get("health", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+	router.Use(al.Middleware)
+
+	ServeRequest(mux, GET, "/v1/health", nil, nil)
+
+	if buffer.Len() != 0 {
+		t.Errorf("Test failed, expected no record got '%s'.", buffer.String())
+	}
+}
+
+func TestAccessLogSamplingKeepsErrors(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	al := badger.NewAccessLog(slog.New(slog.NewJSONHandler(buffer, nil)))
+	al.SampleRate = 0.0000001
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get("users/:id", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusCreated)
+		fmt.Fprint(res, "hello")
+	}))
+	router.Get("fail", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusInternalServerError)
+	}))
+	router.Use(al.Middleware)
+
+	ServeRequest(mux, GET, "/v1/users/42", nil, nil)
+
+	if buffer.Len() != 0 {
+		t.Errorf("Test failed, expected no record got '%s'.", buffer.String())
+	}
+
+	ServeRequest(mux, GET, "/v1/fail", nil, nil)
+
+	if !strings.Contains(buffer.String(), `"status":500`) {
+		t.Errorf("Test failed, expected an error record got '%s'.", buffer.String())
+	}
+}
+
+func TestAccessLogCommonFormat(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get("users/:id", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusCreated)
+		fmt.Fprint(res, "hello")
+	}))
+	router.Use((&badger.AccessLog{Format: badger.AccessLogCommon, Writer: buffer}).Middleware)
+
+	req, _ := http.NewRequest(GET, "/v1/users/42?x=1", nil)
+	req.RemoteAddr = "10.0.0.1:5000"
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+
+	expected := regexp.MustCompile(`^10\.0\.0\.1 - - \[[^\]]+\] "GET /v1/users/42\?x=1 HTTP/1\.1" 201 5\n$`)
+
+	if !expected.MatchString(buffer.String()) {
+		t.Errorf("Test failed, unexpected line '%s'.", buffer.String())
+	}
+}
+
+func TestAccessLogCombinedFormat(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get("users/:id", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusCreated)
+		fmt.Fprint(res, "hello")
+	}))
+	router.Use((&badger.AccessLog{Format: badger.AccessLogCombined, Writer: buffer}).Middleware)
+
+	ServeRequest(mux, GET, "/v1/users/42", nil, map[string]string{"User-Agent": "tester"})
+
+	if !strings.HasSuffix(buffer.String(), `201 5 "-" "tester"`+"\n") {
+		t.Errorf("Test failed, unexpected line '%s'.", buffer.String())
+	}
+}
+
+func TestAccessLogOriginalMethod(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	mux := badger.NewMux()
+	mux.MethodOverride = []string{http.MethodDelete}
+	router := mux.AddRouter(RouterBasePath1)
+	router.Delete("users/:id", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+	router.Use(badger.NewAccessLog(slog.New(slog.NewJSONHandler(buffer, nil))).Middleware)
+
+	ServeRequest(mux, POST, "/v1/users/42", nil, map[string]string{badger.MethodOverrideHeader: http.MethodDelete})
+
+	record := map[string]interface{}{}
+
+	if err := json.Unmarshal(buffer.Bytes(), &record); err != nil {
+		t.Fatalf("Test failed, invalid record '%s'.", buffer.String())
+	}
+
+	if record["method"] != POST || record["method_override"] != http.MethodDelete {
+		t.Errorf("Test failed, unexpected methods '%v' and '%v'.", record["method"], record["method_override"])
+	}
+}
+
+func TestAccessLogRequestIDHeader(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get("users/:id", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+	router.Use((&badger.AccessLog{Format: badger.AccessLogSlog, Logger: slog.New(slog.NewJSONHandler(buffer, nil)), RequestIDHeader: "X-Trace-ID"}).Middleware)
+	router.Use((&badger.RequestID{Header: "X-Trace-ID"}).Middleware)
+
+	ServeRequest(mux, GET, "/v1/users/42", nil, map[string]string{"X-Trace-ID": "abc-123", badger.DefaultRequestIDHeader: "other"})
+
+	if !strings.Contains(buffer.String(), `"request_id":"abc-123"`) {
+		t.Errorf("Test failed, unexpected record '%s'.", buffer.String())
+	}
+}
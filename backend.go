@@ -0,0 +1,68 @@
+package badger
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Param is a single named parameter matched by a Backend
+type Param struct {
+	Key   string
+	Value string
+}
+
+// BackendHandle is the function a Backend calls when a request matches a
+// registered route, along with the params found in the route path
+type BackendHandle func(http.ResponseWriter, *http.Request, []Param)
+
+// Backend is the routing engine used by Mux to match requests against the
+// routes built by every Router. Paths are given in badger syntax, named
+// params as ":name" and catch-all params as "*name". Optional params are
+// expanded by Mux beforehand, so a backend never gets ":name?".
+type Backend interface {
+	// Handle registers a handle for the given method and path, MethodAny
+	// matches the requests of any method with no handle of their own
+	Handle(method string, path string, handle BackendHandle)
+
+	// SetFallbacks sets the handlers used when no route matches the path or
+	// when the path matches but the method does not, nil keeps the backend
+	// default behaviour
+	SetFallbacks(notFound http.Handler, methodNotAllowed http.Handler)
+
+	// Allowed returns the sorted methods with a handle matching the request
+	// path, MethodAny excluded. It is the Allow header of both automatic
+	// OPTIONS and 405 responses.
+	Allowed(req *http.Request) []string
+
+	// ServeHTTP dispatches the request to the matching handle
+	ServeHTTP(http.ResponseWriter, *http.Request)
+}
+
+// expandOptionalPath returns every combination of the given path with and
+// without its optional params, so "/files/:name?" gives "/files/:name" and
+// "/files"
+func expandOptionalPath(path string) []string {
+	expanded := []string{""}
+
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		next := []string{}
+
+		for _, e := range expanded {
+			next = append(next, e+"/"+strings.TrimSuffix(segment, "?"))
+
+			if strings.HasPrefix(segment, ":") && strings.HasSuffix(segment, "?") {
+				next = append(next, e)
+			}
+		}
+
+		expanded = next
+	}
+
+	for i := range expanded {
+		if expanded[i] == "" || strings.HasSuffix(path, "/") {
+			expanded[i] += "/"
+		}
+	}
+
+	return expanded
+}
@@ -0,0 +1,136 @@
+package badger
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+type serveMuxBackend struct {
+	mux              *http.ServeMux
+	methods          map[string]bool
+	notFound         http.Handler
+	methodNotAllowed http.Handler
+}
+
+// NewServeMuxBackend returns a Backend based on the standard library
+// http.ServeMux, which allows static segments to overlap with params, more
+// specific patterns taking precedence
+func NewServeMuxBackend() Backend {
+	return &serveMuxBackend{http.NewServeMux(), map[string]bool{}, nil, nil}
+}
+
+func (b *serveMuxBackend) Handle(method string, path string, handle BackendHandle) {
+	pattern, keys := toServeMuxPattern(path)
+	b.methods[method] = true
+
+	// Patterns with no method match every method
+	if method != MethodAny {
+		pattern = method + " " + pattern
+	}
+
+	b.mux.HandleFunc(pattern, func(res http.ResponseWriter, req *http.Request) {
+		params := make([]Param, 0, len(keys))
+
+		for _, key := range keys {
+			if strings.HasPrefix(key, "*") {
+				// Catch-all values start with / as in httprouter
+				params = append(params, Param{key[1:], "/" + req.PathValue(key[1:])})
+				continue
+			}
+
+			params = append(params, Param{key, req.PathValue(key)})
+		}
+
+		handle(res, req, params)
+	})
+}
+
+func (b *serveMuxBackend) SetFallbacks(notFound http.Handler, methodNotAllowed http.Handler) {
+	b.notFound = notFound
+	b.methodNotAllowed = methodNotAllowed
+}
+
+func (b *serveMuxBackend) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	if _, pattern := b.mux.Handler(req); pattern != "" {
+		b.mux.ServeHTTP(res, req)
+		return
+	}
+
+	if allowed := b.Allowed(req); len(allowed) > 0 {
+		res.Header().Set("Allow", strings.Join(allowed, ", "))
+
+		if b.methodNotAllowed != nil {
+			b.methodNotAllowed.ServeHTTP(res, req)
+			return
+		}
+
+		http.Error(res, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if b.notFound != nil {
+		b.notFound.ServeHTTP(res, req)
+		return
+	}
+
+	http.NotFound(res, req)
+}
+
+func (b *serveMuxBackend) Allowed(req *http.Request) []string {
+	allowed := []string{}
+	probe := req.Clone(req.Context())
+
+	for method := range b.methods {
+		if method == MethodAny {
+			continue
+		}
+
+		probe.Method = method
+
+		if _, pattern := b.mux.Handler(probe); pattern != "" {
+			allowed = append(allowed, method)
+		}
+	}
+
+	sort.Strings(allowed)
+
+	return allowed
+}
+
+// toServeMuxPattern converts a badger route path to a http.ServeMux pattern,
+// returning the param keys found, catch-all keys are prefixed with *
+func toServeMuxPattern(path string) (string, []string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	keys := []string{}
+	pattern := ""
+
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			keys = append(keys, segment[1:])
+			pattern += "/{" + segment[1:] + "}"
+		case strings.HasPrefix(segment, "*"):
+			if i != len(segments)-1 {
+				panic(fmt.Sprintf("catch-all param '%s' must be the last segment in path '%s'", segment, path))
+			}
+
+			keys = append(keys, segment)
+			return pattern + "/{" + segment[1:] + "...}", keys
+		case segment != "":
+			pattern += "/" + segment
+		}
+	}
+
+	if strings.HasSuffix(path, "/") {
+		pattern += "/"
+	}
+
+	// Anchor the pattern, otherwise trailing slash patterns match subtrees
+	if strings.HasSuffix(pattern, "/") {
+		return pattern + "{$}", keys
+	}
+
+	return pattern, keys
+}
@@ -0,0 +1,158 @@
+package badger_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+	"github.com/hugoluchessi/badger/httprouterbackend"
+)
+
+var backends = map[string]func() badger.Backend{
+	"httprouter": httprouterbackend.New,
+	"servemux":   badger.NewServeMuxBackend,
+	"tree":       badger.NewTreeBackend,
+}
+
+func ParamHandlerFunc(headerkey string, param string) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		rp := badger.GetRouteParamsFromRequest(req)
+		value, _ := rp.GetString(param)
+		res.Header().Add(headerkey, value)
+	}
+}
+
+func TestBackendsServeParams(t *testing.T) {
+	for name, backend := range backends {
+		mux := badger.NewMux()
+		mux.Backend = backend()
+		router := mux.AddRouter(RouterBasePath1)
+		router.Get("users/:id", ParamHandlerFunc(RouteHeaderKey1, "id"))
+
+		req, _ := http.NewRequest(GET, "/v1/users/42", nil)
+		res := httptest.NewRecorder()
+		mux.ServeHTTP(res, req)
+
+		if value := res.Header().Get(RouteHeaderKey1); value != "42" {
+			t.Errorf("Test failed for backend '%s', expected param '42' got '%s'.", name, value)
+		}
+	}
+}
+
+func TestBackendsServeCatchAllParams(t *testing.T) {
+	for name, backend := range backends {
+		mux := badger.NewMux()
+		mux.Backend = backend()
+		router := mux.AddRouter(RouterBasePath1)
+		router.Get("files/*filepath", ParamHandlerFunc(RouteHeaderKey1, "filepath"))
+
+		req, _ := http.NewRequest(GET, "/v1/files/css/main.css", nil)
+		res := httptest.NewRecorder()
+		mux.ServeHTTP(res, req)
+
+		if value := res.Header().Get(RouteHeaderKey1); value != "/css/main.css/" {
+			t.Errorf("Test failed for backend '%s', expected param '/css/main.css/' got '%s'.", name, value)
+		}
+	}
+}
+
+func TestBackendsNotFound(t *testing.T) {
+	for name, backend := range backends {
+		mux := badger.NewMux()
+		mux.Backend = backend()
+		router := mux.AddRouter(RouterBasePath1)
+		router.Get(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+		mux.NotFound = http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey2, HeadersExpectedValue))
+
+		req, _ := http.NewRequest(GET, "/v1/nowhere", nil)
+		res := httptest.NewRecorder()
+		mux.ServeHTTP(res, req)
+
+		if value := res.Header().Get(RouteHeaderKey2); value != HeadersExpectedValue {
+			t.Errorf("Test failed for backend '%s', expected not found handler to be called.", name)
+		}
+	}
+}
+
+func TestBackendsMethodNotAllowed(t *testing.T) {
+	for name, backend := range backends {
+		mux := badger.NewMux()
+		mux.Backend = backend()
+		router := mux.AddRouter(RouterBasePath1)
+		router.Get(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+		router.Delete(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+		req, _ := http.NewRequest(POST, "/v1/somepath", nil)
+		res := httptest.NewRecorder()
+		mux.ServeHTTP(res, req)
+
+		if res.Code != http.StatusMethodNotAllowed {
+			t.Errorf("Test failed for backend '%s', expected status %d got %d.", name, http.StatusMethodNotAllowed, res.Code)
+		}
+
+		if allow := res.Header().Get("Allow"); allow == "" {
+			t.Errorf("Test failed for backend '%s', expected Allow header to be set.", name)
+		}
+	}
+}
+
+func TestBackendsMethodAny(t *testing.T) {
+	for name, backend := range backends {
+		mux := badger.NewMux()
+		mux.Backend = backend()
+		router := mux.AddRouter(RouterBasePath1)
+		router.Get("dav/*path", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, "get")))
+		router.Handle(badger.MethodAny, "dav/*path", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+		for method, expected := range map[string]string{GET: "get", POST: HeadersExpectedValue, "PROPFIND": HeadersExpectedValue} {
+			req, _ := http.NewRequest(method, "/v1/dav/files/a.txt", nil)
+			res := httptest.NewRecorder()
+			mux.ServeHTTP(res, req)
+
+			if value := res.Header().Get(RouteHeaderKey1); value != expected {
+				t.Errorf("Test failed for backend '%s', %s expected '%s' got '%s'.", name, method, expected, value)
+			}
+		}
+	}
+}
+
+func TestBackendsOptionalParams(t *testing.T) {
+	for name, backend := range backends {
+		mux := badger.NewMux()
+		mux.Backend = backend()
+		router := mux.AddRouter(RouterBasePath1)
+		router.Get("files/:name?", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			rp := badger.GetRouteParamsFromRequest(req)
+			value, err := rp.GetString("name")
+
+			if err != nil {
+				value = "none"
+			}
+
+			res.Header().Add(RouteHeaderKey1, value)
+		}))
+
+		for url, expected := range map[string]string{"/v1/files/report.pdf": "report.pdf", "/v1/files": "none"} {
+			res := ServeRequest(mux, GET, url, nil, nil)
+
+			if value := res.Header().Get(RouteHeaderKey1); value != expected {
+				t.Errorf("Test failed for backend '%s', %s expected '%s' got '%s'.", name, url, expected, value)
+			}
+		}
+	}
+}
+
+func TestBackendsOverlappingStaticAndParamSegments(t *testing.T) {
+	for _, name := range []string{"servemux", "tree"} {
+		mux := badger.NewMux()
+		mux.Backend = backends[name]()
+		router := mux.AddRouter(RouterBasePath1)
+		router.Get("users/me", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, "me")))
+		router.Get("users/:id", ParamHandlerFunc(RouteHeaderKey1, "id"))
+
+		AssertRoute(t, mux, GET, "/v1/users/me", RouteHeaderKey1, "me")
+		AssertRoute(t, mux, GET, "/v1/users/42", RouteHeaderKey1, "42")
+	}
+}
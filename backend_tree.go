@@ -0,0 +1,219 @@
+package badger
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// RouteConflict is the value a tree Backend panics with when a route can not
+// be registered because it conflicts with an already registered one
+type RouteConflict struct {
+	Method   string
+	Path     string
+	Existing string
+	Reason   string
+}
+
+func (c RouteConflict) Error() string {
+	return fmt.Sprintf("route %s '%s' conflicts with route '%s': %s", c.Method, c.Path, c.Existing, c.Reason)
+}
+
+type treeNode struct {
+	static       map[string]*treeNode
+	param        *treeNode
+	paramKey     string
+	catchAll     *treeNode
+	catchAllKey  string
+	handles      map[string]BackendHandle
+	patterns     map[string]string
+	firstpattern string
+}
+
+type treeMatch struct {
+	method   string
+	trailing bool
+}
+
+type treeBackend struct {
+	root             *treeNode
+	methods          map[string]bool
+	notFound         http.Handler
+	methodNotAllowed http.Handler
+}
+
+func newTreeNode(pattern string) *treeNode {
+	return &treeNode{map[string]*treeNode{}, nil, "", nil, "", map[string]BackendHandle{}, map[string]string{}, pattern}
+}
+
+// NewTreeBackend returns a Backend based on badger's own routing tree, which
+// matches path segments in order of priority, static segments first, then
+// named params and catch-all params last. Catch-all params may appear in the
+// middle of a path and trailing slashes are not significant. Registering a route which can
+// never be matched panics with a RouteConflict.
+func NewTreeBackend() Backend {
+	return &treeBackend{newTreeNode(""), map[string]bool{}, nil, nil}
+}
+
+func (b *treeBackend) Handle(method string, path string, handle BackendHandle) {
+	node := b.root
+
+	for _, segment := range splitPath(path) {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			if node.param == nil {
+				node.param = newTreeNode(path)
+				node.paramKey = segment[1:]
+			} else if node.paramKey != segment[1:] {
+				panic(RouteConflict{method, path, node.param.firstpattern, fmt.Sprintf("param '%s' is already named ':%s'", segment, node.paramKey)})
+			}
+
+			node = node.param
+		case strings.HasPrefix(segment, "*"):
+			if node.catchAll == nil {
+				node.catchAll = newTreeNode(path)
+				node.catchAllKey = segment[1:]
+			} else if node.catchAllKey != segment[1:] {
+				panic(RouteConflict{method, path, node.catchAll.firstpattern, fmt.Sprintf("catch-all '%s' is already named '*%s'", segment, node.catchAllKey)})
+			}
+
+			node = node.catchAll
+		default:
+			if _, ok := node.static[segment]; !ok {
+				node.static[segment] = newTreeNode(path)
+			}
+
+			node = node.static[segment]
+		}
+	}
+
+	if existing, ok := node.patterns[method]; ok {
+		panic(RouteConflict{method, path, existing, "both routes match the same paths"})
+	}
+
+	node.handles[method] = handle
+	node.patterns[method] = path
+	b.methods[method] = true
+}
+
+func (b *treeBackend) SetFallbacks(notFound http.Handler, methodNotAllowed http.Handler) {
+	b.notFound = notFound
+	b.methodNotAllowed = methodNotAllowed
+}
+
+func (b *treeBackend) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	segments := splitPath(req.URL.Path)
+	trailing := strings.HasSuffix(req.URL.Path, "/")
+
+	if handle, params := b.root.lookup(segments, treeMatch{req.Method, trailing}, []Param{}); handle != nil {
+		handle(res, req, params)
+		return
+	}
+
+	if handle, params := b.root.lookup(segments, treeMatch{MethodAny, trailing}, []Param{}); handle != nil {
+		handle(res, req, params)
+		return
+	}
+
+	if allowed := b.Allowed(req); len(allowed) > 0 {
+		res.Header().Set("Allow", strings.Join(allowed, ", "))
+
+		if b.methodNotAllowed != nil {
+			b.methodNotAllowed.ServeHTTP(res, req)
+			return
+		}
+
+		http.Error(res, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if b.notFound != nil {
+		b.notFound.ServeHTTP(res, req)
+		return
+	}
+
+	http.NotFound(res, req)
+}
+
+func (b *treeBackend) Allowed(req *http.Request) []string {
+	segments := splitPath(req.URL.Path)
+	allowed := []string{}
+
+	for method := range b.methods {
+		if method == MethodAny {
+			continue
+		}
+
+		if handle, _ := b.root.lookup(segments, treeMatch{method, false}, []Param{}); handle != nil {
+			allowed = append(allowed, method)
+		}
+	}
+
+	sort.Strings(allowed)
+
+	return allowed
+}
+
+// lookup walks the tree looking for a handle for the given method, trying
+// static segments before params and params before catch-alls, catch-alls
+// consume as few segments as possible
+func (n *treeNode) lookup(segments []string, m treeMatch, params []Param) (BackendHandle, []Param) {
+	if len(segments) == 0 {
+		if handle, ok := n.handles[m.method]; ok {
+			return handle, params
+		}
+
+		// Catch-alls also match an empty remainder
+		if n.catchAll != nil {
+			if handle, ok := n.catchAll.handles[m.method]; ok {
+				return handle, append(params, Param{n.catchAllKey, "/"})
+			}
+		}
+
+		return nil, nil
+	}
+
+	if child, ok := n.static[segments[0]]; ok {
+		if handle, found := child.lookup(segments[1:], m, params); handle != nil {
+			return handle, found
+		}
+	}
+
+	if n.param != nil {
+		found := append(params[:len(params):len(params)], Param{n.paramKey, segments[0]})
+
+		if handle, found := n.param.lookup(segments[1:], m, found); handle != nil {
+			return handle, found
+		}
+	}
+
+	if n.catchAll != nil {
+		for i := 1; i <= len(segments); i++ {
+			value := "/" + strings.Join(segments[:i], "/")
+
+			// Catch-alls ending the path keep its trailing slash
+			if i == len(segments) && m.trailing {
+				value += "/"
+			}
+
+			found := append(params[:len(params):len(params)], Param{n.catchAllKey, value})
+
+			if handle, found := n.catchAll.lookup(segments[i:], m, found); handle != nil {
+				return handle, found
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+
+	if path == "" {
+		return []string{}
+	}
+
+	return strings.Split(path, "/")
+}
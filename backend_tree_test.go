@@ -0,0 +1,86 @@
+package badger_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+func AssertRouteConflict(t *testing.T, register func()) {
+	defer func() {
+		p := recover()
+
+		if _, ok := p.(badger.RouteConflict); !ok {
+			t.Errorf("Test failed, expected a RouteConflict panic got '%v'.", p)
+		}
+	}()
+
+	register()
+}
+
+func TestTreeBackendPriority(t *testing.T) {
+	mux := badger.NewMux()
+	mux.Backend = badger.NewTreeBackend()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get("users/*rest", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, "catchall")))
+	router.Get("users/:id", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, "param")))
+	router.Get("users/me", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, "static")))
+
+	AssertRoute(t, mux, GET, "/v1/users/me", RouteHeaderKey1, "static")
+	AssertRoute(t, mux, GET, "/v1/users/42", RouteHeaderKey1, "param")
+	AssertRoute(t, mux, GET, "/v1/users/42/friends", RouteHeaderKey1, "catchall")
+}
+
+func TestTreeBackendFallsBackToParamForOtherMethods(t *testing.T) {
+	mux := badger.NewMux()
+	mux.Backend = badger.NewTreeBackend()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get("users/me", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, "static")))
+	router.Delete("users/:id", ParamHandlerFunc(RouteHeaderKey1, "id"))
+
+	AssertRoute(t, mux, "DELETE", "/v1/users/me", RouteHeaderKey1, "me")
+}
+
+func TestTreeBackendMidPathCatchAll(t *testing.T) {
+	mux := badger.NewMux()
+	mux.Backend = badger.NewTreeBackend()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get("repos/*repo/blob/:file", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		rp := badger.GetRouteParamsFromRequest(req)
+		repo, _ := rp.GetString("repo")
+		file, _ := rp.GetString("file")
+		res.Header().Add(RouteHeaderKey1, repo)
+		res.Header().Add(RouteHeaderKey2, file)
+	}))
+
+	AssertRoute(t, mux, GET, "/v1/repos/org/badger/blob/mux.go", RouteHeaderKey1, "/org/badger")
+	AssertRoute(t, mux, GET, "/v1/repos/org/badger/blob/mux.go", RouteHeaderKey2, "mux.go")
+}
+
+func TestTreeBackendDuplicateRouteConflict(t *testing.T) {
+	backend := badger.NewTreeBackend()
+	backend.Handle(GET, "/files/", nil)
+
+	AssertRouteConflict(t, func() {
+		backend.Handle(GET, "/files", nil)
+	})
+}
+
+func TestTreeBackendParamNameConflict(t *testing.T) {
+	backend := badger.NewTreeBackend()
+	backend.Handle(GET, "/users/:id/", nil)
+
+	AssertRouteConflict(t, func() {
+		backend.Handle(POST, "/users/:name/", nil)
+	})
+}
+
+func TestRouteConflictError(t *testing.T) {
+	conflict := badger.RouteConflict{Method: GET, Path: "/b/", Existing: "/a/", Reason: "why"}
+	expected := "route GET '/b/' conflicts with route '/a/': why"
+
+	if conflict.Error() != expected {
+		t.Errorf("Test failed, expected '%s' got '%s'.", expected, conflict.Error())
+	}
+}
@@ -0,0 +1,330 @@
+package badger
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DefaultCompressionMinSize is the default minimum body size compressed
+const DefaultCompressionMinSize = 1024
+
+// DefaultCompressionContentTypes are the content types compressed by
+// default, entries ending with "/" match every subtype
+var DefaultCompressionContentTypes = []string{
+	"text/",
+	"application/json",
+	"application/problem+json",
+	"application/javascript",
+	"application/xml",
+	"image/svg+xml",
+}
+
+var compressionPolicyKey = NewMetaKey[*Compression]("compression")
+
+// Encoder compresses response bodies with a content coding
+type Encoder struct {
+	// Coding is the Content-Encoding name, such as "gzip" or "br"
+	Coding string
+	// NewWriter returns a writer compressing to w, the body is complete once
+	// it is closed
+	NewWriter func(w io.Writer) (io.WriteCloser, error)
+}
+
+// NewGzipEncoder returns a gzip Encoder with the given compression level
+func NewGzipEncoder(level int) Encoder {
+	return Encoder{"gzip", func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriterLevel(w, level)
+	}}
+}
+
+// NewDeflateEncoder returns a deflate Encoder with the given compression
+// level, HTTP deflate being the zlib format
+func NewDeflateEncoder(level int) Encoder {
+	return Encoder{"deflate", func(w io.Writer) (io.WriteCloser, error) {
+		return zlib.NewWriterLevel(w, level)
+	}}
+}
+
+// Compression compresses responses with the best encoding accepted by the
+// client. Set it on a router or a route, or use it as a plain middleware.
+// Responses already encoded, partial, event streams and responses flushed
+// before reaching MinSize are sent as is.
+type Compression struct {
+	// Encoders in order of preference when the client gives several the
+	// same quality, defaults to gzip and deflate
+	Encoders []Encoder
+	// MinSize is the minimum body size compressed, defaults to
+	// DefaultCompressionMinSize
+	MinSize int
+	// ContentTypes lists the content types compressed, defaults to
+	// DefaultCompressionContentTypes
+	ContentTypes []string
+}
+
+// NewCompression returns a Compression with gzip and deflate at their
+// default level
+func NewCompression() *Compression {
+	return &Compression{}
+}
+
+// SetCompression sets the response compression of every route of the
+// router, routes may override it with WithCompression
+func (r *Router) SetCompression(c *Compression) {
+	r.WithMeta(compressionPolicyKey, c)
+}
+
+// WithCompression sets the response compression of the route and returns
+// it, nil disables compression for the route
+func (r *Route) WithCompression(c *Compression) *Route {
+	return r.WithMeta(compressionPolicyKey, c)
+}
+
+// Middleware compresses the responses of the given handler
+func (c *Compression) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// Hijacked connections are never compressed
+		if req.Header.Get("Upgrade") != "" {
+			h.ServeHTTP(res, req)
+			return
+		}
+
+		cw := &compressWriter{res, c, c.negotiate(req.Header.Get("Accept-Encoding")), nil, nil, 0, false}
+		h.ServeHTTP(cw, req)
+
+		// Not deferred, on panic nothing is sent so the recovery can answer
+		cw.Close()
+	})
+}
+
+// negotiate returns the encoder with the highest quality, nil when the
+// client accepts none
+func (c *Compression) negotiate(accept string) *Encoder {
+	var best *Encoder
+	quality := 0.0
+
+	for i, encoder := range c.encoders() {
+		if q := encodingQuality(accept, encoder.Coding); q > quality {
+			best, quality = &c.encoders()[i], q
+		}
+	}
+
+	// Identity may be explicitly preferred over every encoding
+	if best != nil && strings.Contains(strings.ToLower(accept), "identity") && encodingQuality(accept, "identity") > quality {
+		return nil
+	}
+
+	return best
+}
+
+func (c *Compression) encoders() []Encoder {
+	if len(c.Encoders) == 0 {
+		return defaultEncoders
+	}
+
+	return c.Encoders
+}
+
+var defaultEncoders = []Encoder{NewGzipEncoder(gzip.DefaultCompression), NewDeflateEncoder(zlib.DefaultCompression)}
+
+func (c *Compression) minSize() int {
+	if c.MinSize <= 0 {
+		return DefaultCompressionMinSize
+	}
+
+	return c.MinSize
+}
+
+func (c *Compression) compressible(contenttype string) bool {
+	mediatype, _, err := mime.ParseMediaType(contenttype)
+
+	if err != nil || mediatype == "text/event-stream" {
+		return false
+	}
+
+	types := c.ContentTypes
+
+	if len(types) == 0 {
+		types = DefaultCompressionContentTypes
+	}
+
+	for _, t := range types {
+		if mediatype == t || strings.HasSuffix(t, "/") && strings.HasPrefix(mediatype, t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compressWriter buffers the beginning of the body until it can tell
+// whether the response is worth compressing
+type compressWriter struct {
+	http.ResponseWriter
+	c       *Compression
+	encoder *Encoder
+	buf     []byte
+	w       io.WriteCloser
+	status  int
+	decided bool
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.decided || cw.status != 0 {
+		return
+	}
+
+	// Informational responses are sent right away
+	if status >= 100 && status <= 199 {
+		cw.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	cw.status = status
+
+	if !bodyAllowedForStatus(status) {
+		cw.decide(nil, false)
+	}
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if !cw.decided {
+		n := len(b)
+		cw.buf = append(cw.buf, b...)
+
+		if len(cw.buf) < cw.c.minSize() {
+			return n, nil
+		}
+
+		b, cw.buf = cw.buf, nil
+
+		if err := cw.decide(b, true); err != nil {
+			return 0, err
+		}
+
+		_, err := cw.write(b)
+		return n, err
+	}
+
+	return cw.write(b)
+}
+
+func (cw *compressWriter) write(b []byte) (int, error) {
+	if cw.w != nil {
+		return cw.w.Write(b)
+	}
+
+	return cw.ResponseWriter.Write(b)
+}
+
+// Flush sends the buffered body, responses flushed before reaching the
+// minimum size are not compressed
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		b := cw.buf
+		cw.buf = nil
+
+		if cw.decide(b, false) == nil {
+			cw.write(b)
+		}
+	}
+
+	if f, ok := cw.w.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// Close sends what is left of the body and ends the compressed stream
+func (cw *compressWriter) Close() error {
+	if !cw.decided {
+		b := cw.buf
+		cw.buf = nil
+
+		if err := cw.decide(b, false); err != nil {
+			return err
+		}
+
+		if len(b) > 0 {
+			cw.write(b)
+		}
+	}
+
+	if cw.w != nil {
+		return cw.w.Close()
+	}
+
+	return nil
+}
+
+// decide writes the header, compressing the body starting with b if large
+// is true and the response can be compressed
+func (cw *compressWriter) decide(b []byte, large bool) error {
+	cw.decided = true
+	header := cw.Header()
+
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+
+	if header.Get("Content-Type") == "" && len(b) > 0 {
+		header.Set("Content-Type", http.DetectContentType(b))
+	}
+
+	eligible := bodyAllowedForStatus(cw.status) && cw.status != http.StatusPartialContent &&
+		header.Get("Content-Encoding") == "" && header.Get("Content-Range") == "" &&
+		cw.c.compressible(header.Get("Content-Type"))
+
+	if eligible {
+		header.Add("Vary", "Accept-Encoding")
+	}
+
+	if !eligible || !large || cw.encoder == nil {
+		cw.ResponseWriter.WriteHeader(cw.status)
+		return nil
+	}
+
+	w, err := cw.encoder.NewWriter(cw.ResponseWriter)
+
+	if err != nil {
+		cw.ResponseWriter.WriteHeader(cw.status)
+		return err
+	}
+
+	cw.w = w
+	header.Set("Content-Encoding", cw.encoder.Coding)
+	header.Del("Content-Length")
+
+	// The compressed representation differs byte for byte
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	return nil
+}
+
+func bodyAllowedForStatus(status int) bool {
+	return status != http.StatusNoContent && status != http.StatusNotModified && (status < 100 || status > 199)
+}
+
+// applyCompression wraps the routes with their compression policy
+func applyCompression(routes []builtRoute) {
+	for i, route := range routes {
+		if c, _ := compressionPolicyKey.FromRoute(route.route); c != nil {
+			routes[i].handler = c.Middleware(route.handler)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package badger_test
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+var CompressBody = strings.Repeat("compress me ", 200)
+
+var AcceptGzip = map[string]string{"Accept-Encoding": "gzip"}
+
+// TextHandlerFunc writes CompressBody in two writes
+func TextHandlerFunc(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	res.Header().Set("ETag", `"v1"`)
+	fmt.Fprint(res, CompressBody[:600])
+	fmt.Fprint(res, CompressBody[600:])
+}
+
+func Decompress(t *testing.T, res *httptest.ResponseRecorder) string {
+	t.Helper()
+
+	var r io.Reader
+
+	switch res.Header().Get("Content-Encoding") {
+	case "gzip":
+		gr, err := gzip.NewReader(res.Body)
+
+		if err != nil {
+			t.Fatalf("Test failed, invalid gzip body '%s'.", err)
+		}
+
+		r = gr
+	case "deflate":
+		zr, err := zlib.NewReader(res.Body)
+
+		if err != nil {
+			t.Fatalf("Test failed, invalid zlib body '%s'.", err)
+		}
+
+		r = zr
+	default:
+		r = res.Body
+	}
+
+	body, err := io.ReadAll(r)
+
+	if err != nil {
+		t.Fatalf("Test failed, invalid body '%s'.", err)
+	}
+
+	return string(body)
+}
+
+func TestCompressionNegotiation(t *testing.T) {
+	tests := map[string]string{
+		"gzip":                       "gzip",
+		"deflate, gzip":              "gzip",
+		"gzip;q=0.5, deflate":        "deflate",
+		"deflate;q=0.2, *;q=0.5":     "gzip",
+		"gzip;q=0, deflate;q=0":      "",
+		"br":                         "",
+		"":                           "",
+		"gzip;q=0.5, identity":       "",
+		"GZIP;q=0.8, identity;q=0.1": "gzip",
+	}
+
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetCompression(badger.NewCompression())
+	router.Get("text", http.HandlerFunc(TextHandlerFunc))
+
+	for accept, expected := range tests {
+		res := ServeRequest(mux, GET, "/v1/text", nil, map[string]string{"Accept-Encoding": accept})
+
+		if encoding := res.Header().Get("Content-Encoding"); encoding != expected {
+			t.Errorf("Test failed, '%s' expected encoding '%s' got '%s'.", accept, expected, encoding)
+		}
+
+		if body := Decompress(t, res); body != CompressBody {
+			t.Errorf("Test failed, '%s' unexpected body of length %d.", accept, len(body))
+		}
+
+		if vary := res.Header().Get("Vary"); vary != "Accept-Encoding" {
+			t.Errorf("Test failed, expected Vary header got '%s'.", vary)
+		}
+	}
+}
+
+func TestCompressionHeaders(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetCompression(badger.NewCompression())
+	router.Get("text", http.HandlerFunc(TextHandlerFunc))
+
+	res := ServeRequest(mux, GET, "/v1/text", nil, AcceptGzip)
+
+	if res.Header().Get("ETag") != `W/"v1"` || res.Header().Get("Content-Length") != "" {
+		t.Errorf("Test failed, unexpected headers '%v'.", res.Header())
+	}
+
+	if res.Body.Len() >= len(CompressBody) {
+		t.Errorf("Test failed, expected a compressed body got %d bytes.", res.Body.Len())
+	}
+}
+
+func TestCompressionSkipped(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetCompression(badger.NewCompression())
+
+	router.Get("small", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(res, "tiny")
+	}))
+	router.Get("image", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "image/png")
+		fmt.Fprint(res, CompressBody)
+	}))
+	router.Get("encoded", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "text/plain")
+		res.Header().Set("Content-Encoding", "br")
+		fmt.Fprint(res, CompressBody)
+	}))
+	router.Get("events", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(res, CompressBody)
+	}))
+	router.Get("stream", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(res, "first")
+		res.(http.Flusher).Flush()
+		fmt.Fprint(res, CompressBody)
+	}))
+	router.Get("plain", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(res, CompressBody)
+	})).WithCompression(nil)
+
+	for _, url := range []string{"/v1/small", "/v1/image", "/v1/encoded", "/v1/events", "/v1/stream", "/v1/plain"} {
+		res := ServeRequest(mux, GET, url, nil, AcceptGzip)
+
+		if encoding := res.Header().Get("Content-Encoding"); encoding != "" && encoding != "br" {
+			t.Errorf("Test failed, '%s' expected no compression got '%s'.", url, encoding)
+		}
+
+		if !strings.HasSuffix(res.Body.String(), "tiny") && !strings.HasSuffix(res.Body.String(), CompressBody) {
+			t.Errorf("Test failed, '%s' unexpected body.", url)
+		}
+	}
+}
+
+func TestCompressionSniffsContentType(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetCompression(badger.NewCompression())
+	router.Get("sniffed", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(res, CompressBody)
+	}))
+
+	res := ServeRequest(mux, GET, "/v1/sniffed", nil, AcceptGzip)
+
+	if res.Header().Get("Content-Encoding") != "gzip" || res.Header().Get("Content-Type") != "text/plain; charset=utf-8" {
+		t.Errorf("Test failed, unexpected headers '%v'.", res.Header())
+	}
+}
+
+func TestCompressionKeepsFlusher(t *testing.T) {
+	c := &badger.Compression{MinSize: 10}
+	flushed := false
+
+	handler := c.Middleware(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(res, CompressBody)
+
+		if err := http.NewResponseController(res).Flush(); err != nil {
+			t.Errorf("Test failed, unexpected flush error '%s'.", err)
+		}
+
+		flushed = true
+		fmt.Fprint(res, CompressBody)
+	}))
+
+	res := ServeRequest(handler, GET, "/", nil, AcceptGzip)
+
+	if !flushed || !res.Flushed {
+		t.Error("Test failed, expected the response to be flushed.")
+	}
+
+	if body := Decompress(t, res); body != CompressBody+CompressBody {
+		t.Errorf("Test failed, unexpected body of length %d.", len(body))
+	}
+}
+
+type UpperWriter struct {
+	w io.Writer
+}
+
+func (u UpperWriter) Write(b []byte) (int, error) {
+	return u.w.Write([]byte(strings.ToUpper(string(b))))
+}
+
+func (u UpperWriter) Close() error {
+	return nil
+}
+
+func TestCompressionCustomEncoder(t *testing.T) {
+	c := &badger.Compression{
+		Encoders:     []badger.Encoder{{Coding: "upper", NewWriter: func(w io.Writer) (io.WriteCloser, error) { return UpperWriter{w}, nil }}},
+		ContentTypes: []string{"text/plain"},
+	}
+
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetCompression(c)
+	router.Get("text", http.HandlerFunc(TextHandlerFunc))
+
+	res := ServeRequest(mux, GET, "/v1/text", nil, map[string]string{"Accept-Encoding": "upper, gzip"})
+
+	if res.Header().Get("Content-Encoding") != "upper" || res.Body.String() != strings.ToUpper(CompressBody) {
+		t.Errorf("Test failed, unexpected response '%v'.", res.Header())
+	}
+}
+
+func TestCompressionStatus(t *testing.T) {
+	handler := badger.NewCompression().Middleware(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "text/plain")
+		res.WriteHeader(http.StatusCreated)
+		fmt.Fprint(res, CompressBody)
+	}))
+
+	res := ServeRequest(handler, GET, "/", nil, AcceptGzip)
+
+	if res.Code != http.StatusCreated || Decompress(t, res) != CompressBody {
+		t.Errorf("Test failed, unexpected response %d.", res.Code)
+	}
+}
+
+func TestCompressionPanicRecovered(t *testing.T) {
+	mux := badger.NewMux()
+	mux.PanicHandler = badger.NewRecovery(func(req *http.Request, err *badger.PanicError) {}).PanicHandler
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetCompression(badger.NewCompression())
+	router.Get("panic", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(res, "partial")
+		panic("oops")
+	}))
+
+	res := ServeRequest(mux, GET, "/v1/panic", nil, AcceptGzip)
+
+	if res.Code != http.StatusInternalServerError || strings.Contains(res.Body.String(), "partial") {
+		t.Errorf("Test failed, expected a 500 got %d '%s'.", res.Code, res.Body.String())
+	}
+}
+
+func TestCompressionAutoHead(t *testing.T) {
+	mux := badger.NewMux()
+	mux.AutoHead = true
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetCompression(badger.NewCompression())
+	router.Get("text", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(res, CompressBody)
+	}))
+
+	get := ServeRequest(mux, GET, "/v1/text", nil, AcceptGzip)
+	head := ServeRequest(mux, http.MethodHead, "/v1/text", nil, AcceptGzip)
+
+	for _, key := range []string{"Content-Encoding", "Vary", "Content-Type"} {
+		if head.Header().Get(key) == "" || head.Header().Get(key) != get.Header().Get(key) {
+			t.Errorf("Test failed, expected HEAD '%s' to be '%s' got '%s'.", key, get.Header().Get(key), head.Header().Get(key))
+		}
+	}
+
+	if length := head.Header().Get("Content-Length"); length != fmt.Sprint(get.Body.Len()) || head.Body.Len() != 0 {
+		t.Errorf("Test failed, expected the compressed length %d got '%s'.", get.Body.Len(), length)
+	}
+}
@@ -0,0 +1,303 @@
+package badger
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORS request and response headers
+const (
+	CORSAllowOriginHeader           = "Access-Control-Allow-Origin"
+	CORSAllowCredentialsHeader      = "Access-Control-Allow-Credentials"
+	CORSAllowMethodsHeader          = "Access-Control-Allow-Methods"
+	CORSAllowHeadersHeader          = "Access-Control-Allow-Headers"
+	CORSExposeHeadersHeader         = "Access-Control-Expose-Headers"
+	CORSMaxAgeHeader                = "Access-Control-Max-Age"
+	CORSAllowPrivateNetworkHeader   = "Access-Control-Allow-Private-Network"
+	CORSRequestMethodHeader         = "Access-Control-Request-Method"
+	CORSRequestHeadersHeader        = "Access-Control-Request-Headers"
+	CORSRequestPrivateNetworkHeader = "Access-Control-Request-Private-Network"
+)
+
+var corsPolicyKey = NewMetaKey[*CORS]("cors")
+
+// CORS is a cross origin resource sharing policy. Set it on a router or a
+// route, the Mux then answers preflight requests for every path through
+// the automatic OPTIONS routes, and adds the CORS headers to the responses.
+// It can also be used as a plain middleware.
+type CORS struct {
+	// AllowedOrigins lists the allowed origins, such as
+	// "https://example.com". An origin may contain a "*" matching one or
+	// more characters, such as "https://*.example.com", and "*" allows
+	// every origin, it can't be combined with AllowCredentials.
+	AllowedOrigins []string
+	// AllowedOriginPatterns lists regular expressions matching allowed
+	// origins
+	AllowedOriginPatterns []*regexp.Regexp
+	// AllowedMethods lists the methods allowed in preflight requests,
+	// defaults to the methods registered for the path, or GET, HEAD and
+	// POST when used as a plain middleware
+	AllowedMethods []string
+	// AllowedHeaders lists the request headers allowed in preflight
+	// requests, "*" allows any. Every requested header is allowed when
+	// empty.
+	AllowedHeaders []string
+	// ExposedHeaders lists the response headers readable by clients
+	ExposedHeaders []string
+	// AllowCredentials allows requests with cookies and authorization,
+	// the origin is then echoed instead of "*"
+	AllowCredentials bool
+	// MaxAge is how long preflight responses may be cached, not sent when 0
+	MaxAge time.Duration
+	// AllowPrivateNetwork allows public pages to reach this private network
+	// server, answering Private Network Access preflight requests
+	AllowPrivateNetwork bool
+}
+
+// SetCORS sets the CORS policy of every route of the router, routes may
+// override it with WithCORS. It panics if the policy allows credentials
+// from every origin.
+func (r *Router) SetCORS(policy *CORS) {
+	policy.validate()
+	r.WithMeta(corsPolicyKey, policy)
+}
+
+// WithCORS sets the CORS policy of the route and returns it. It panics if
+// the policy allows credentials from every origin.
+func (r *Route) WithCORS(policy *CORS) *Route {
+	policy.validate()
+	return r.WithMeta(corsPolicyKey, policy)
+}
+
+// Middleware applies the policy to the requests of the given handler,
+// answering preflight requests itself. It panics if the policy allows
+// credentials from every origin.
+func (c *CORS) Middleware(h http.Handler) http.Handler {
+	c.validate()
+	return c.handler(nil, h)
+}
+
+// validate rejects "*" origins with credentials, any site could then read
+// the responses of signed in users
+func (c *CORS) validate() {
+	if c != nil && c.AllowCredentials && slices.Contains(c.AllowedOrigins, "*") {
+		panic("cors: AllowedOrigins '*' can't be combined with AllowCredentials")
+	}
+}
+
+// handler applies the policy, methods are the methods registered for the
+// path
+func (c *CORS) handler(methods []string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if isPreflight(req) {
+			c.preflight(res, req, methods)
+			return
+		}
+
+		c.actual(res, req)
+		h.ServeHTTP(res, req)
+	})
+}
+
+func (c *CORS) actual(res http.ResponseWriter, req *http.Request) {
+	header := res.Header()
+	header.Add("Vary", "Origin")
+
+	origin := req.Header.Get("Origin")
+
+	if origin == "" || !c.allowOrigin(header, origin) {
+		return
+	}
+
+	if len(c.ExposedHeaders) > 0 {
+		header.Set(CORSExposeHeadersHeader, strings.Join(c.ExposedHeaders, ", "))
+	}
+}
+
+func (c *CORS) preflight(res http.ResponseWriter, req *http.Request, methods []string) {
+	header := res.Header()
+	header.Add("Vary", "Origin")
+	header.Add("Vary", CORSRequestMethodHeader)
+	header.Add("Vary", CORSRequestHeadersHeader)
+
+	if c.AllowPrivateNetwork {
+		header.Add("Vary", CORSRequestPrivateNetworkHeader)
+	}
+
+	// Without CORS headers the browser rejects the request
+	defer res.WriteHeader(http.StatusNoContent)
+
+	if !c.allowMethod(req.Header.Get(CORSRequestMethodHeader), methods) {
+		return
+	}
+
+	requested := parseHeaderList(req.Header.Values(CORSRequestHeadersHeader))
+
+	for _, h := range requested {
+		if !c.allowHeader(h) {
+			return
+		}
+	}
+
+	if !c.allowOrigin(header, req.Header.Get("Origin")) {
+		return
+	}
+
+	header.Set(CORSAllowMethodsHeader, req.Header.Get(CORSRequestMethodHeader))
+
+	if len(requested) > 0 {
+		header.Set(CORSAllowHeadersHeader, strings.Join(requested, ", "))
+	}
+
+	if c.MaxAge > 0 {
+		header.Set(CORSMaxAgeHeader, strconv.Itoa(int(c.MaxAge.Seconds())))
+	}
+
+	if c.AllowPrivateNetwork && req.Header.Get(CORSRequestPrivateNetworkHeader) == "true" {
+		header.Set(CORSAllowPrivateNetworkHeader, "true")
+	}
+}
+
+// allowOrigin sets the allow origin headers if origin is allowed, only
+// origins matched explicitly are echoed with credentials
+func (c *CORS) allowOrigin(header http.Header, origin string) bool {
+	if !c.AllowCredentials && slices.Contains(c.AllowedOrigins, "*") {
+		header.Set(CORSAllowOriginHeader, "*")
+		return true
+	}
+
+	if !c.matchOrigin(origin) {
+		return false
+	}
+
+	header.Set(CORSAllowOriginHeader, origin)
+
+	if c.AllowCredentials {
+		header.Set(CORSAllowCredentialsHeader, "true")
+	}
+
+	return true
+}
+
+func (c *CORS) matchOrigin(origin string) bool {
+	origin = strings.ToLower(origin)
+
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" {
+			continue
+		}
+
+		allowed = strings.ToLower(allowed)
+		prefix, suffix, wildcard := strings.Cut(allowed, "*")
+
+		if origin == allowed || wildcard && len(origin) > len(prefix)+len(suffix) &&
+			strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+
+	for _, pattern := range c.AllowedOriginPatterns {
+		if pattern.MatchString(origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *CORS) allowMethod(method string, methods []string) bool {
+	allowed := c.AllowedMethods
+
+	if len(allowed) == 0 {
+		allowed = methods
+	}
+
+	if len(allowed) == 0 {
+		allowed = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+	}
+
+	return slices.Contains(allowed, method) || slices.Contains(allowed, MethodAny)
+}
+
+func (c *CORS) allowHeader(h string) bool {
+	if len(c.AllowedHeaders) == 0 {
+		return true
+	}
+
+	for _, allowed := range c.AllowedHeaders {
+		if allowed == "*" || strings.EqualFold(allowed, h) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isPreflight(req *http.Request) bool {
+	return req.Method == http.MethodOptions && req.Header.Get("Origin") != "" && req.Header.Get(CORSRequestMethodHeader) != ""
+}
+
+func parseHeaderList(values []string) []string {
+	headers := []string{}
+
+	for _, value := range values {
+		for _, h := range strings.Split(value, ",") {
+			if h = strings.TrimSpace(h); h != "" {
+				headers = append(headers, strings.ToLower(h))
+			}
+		}
+	}
+
+	return headers
+}
+
+// applyCORS wraps the routes with their CORS policy, OPTIONS routes answer
+// preflight requests with the policy of the requested method route
+func applyCORS(routes []builtRoute, allowed map[string][]string) {
+	policies := map[string]map[string]*CORS{}
+
+	for _, route := range routes {
+		policy, _ := corsPolicyKey.FromRoute(route.route)
+
+		if policy == nil || route.method == http.MethodOptions {
+			continue
+		}
+
+		if policies[route.path] == nil {
+			policies[route.path] = map[string]*CORS{}
+		}
+
+		policies[route.path][route.method] = policy
+	}
+
+	for i, route := range routes {
+		policy, _ := corsPolicyKey.FromRoute(route.route)
+
+		if route.method == http.MethodOptions && (policy != nil || len(policies[route.path]) > 0) {
+			routes[i].handler = preflightHandler(policy, policies[route.path], allowed[route.path], route.handler)
+		} else if policy != nil {
+			routes[i].handler = policy.handler(allowed[route.path], route.handler)
+		}
+	}
+}
+
+func preflightHandler(policy *CORS, policies map[string]*CORS, methods []string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		p := policy
+
+		if isPreflight(req) && policies[req.Header.Get(CORSRequestMethodHeader)] != nil {
+			p = policies[req.Header.Get(CORSRequestMethodHeader)]
+		}
+
+		if p == nil {
+			h.ServeHTTP(res, req)
+			return
+		}
+
+		p.handler(methods, h).ServeHTTP(res, req)
+	})
+}
@@ -0,0 +1,245 @@
+package badger_test
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hugoluchessi/badger"
+)
+
+// AppCORS returns the policy of the app.example.com tests
+func AppCORS() *badger.CORS {
+	return &badger.CORS{
+		AllowedOrigins:        []string{"https://app.example.com", "https://*.example.org"},
+		AllowedOriginPatterns: []*regexp.Regexp{regexp.MustCompile(`^http://localhost:\d+$`)},
+		ExposedHeaders:        []string{"X-Total"},
+		MaxAge:                10 * time.Minute,
+		AllowPrivateNetwork:   true,
+	}
+}
+
+// AdminCORS returns the route policy of the admin.example.com tests
+func AdminCORS() *badger.CORS {
+	return &badger.CORS{AllowedOrigins: []string{"https://admin.example.com"}, AllowCredentials: true}
+}
+
+// RequireAuthorization rejects requests with no credentials, as preflight
+// requests are
+func RequireAuthorization(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") == "" {
+			res.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+// Preflight returns the headers of a preflight request
+func Preflight(origin string, method string, headers string) map[string]string {
+	return map[string]string{"Origin": origin, badger.CORSRequestMethodHeader: method, badger.CORSRequestHeadersHeader: headers}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetCORS(AppCORS())
+	router.Use(RequireAuthorization)
+	router.Get("users", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+	router.Post("users", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+	res := ServeRequest(mux, http.MethodOptions, "/v1/users", nil, Preflight("https://app.example.com", POST, "Content-Type, X-Custom"))
+
+	if res.Code != http.StatusNoContent {
+		t.Errorf("Test failed, expected status 204 got %d.", res.Code)
+	}
+
+	expected := map[string]string{
+		badger.CORSAllowOriginHeader:  "https://app.example.com",
+		badger.CORSAllowMethodsHeader: POST,
+		badger.CORSAllowHeadersHeader: "content-type, x-custom",
+		badger.CORSMaxAgeHeader:       "600",
+	}
+
+	for key, value := range expected {
+		if got := res.Header().Get(key); got != value {
+			t.Errorf("Test failed, expected header '%s' to be '%s' got '%s'.", key, value, got)
+		}
+	}
+
+	if vary := res.Header().Values("Vary"); len(vary) != 4 {
+		t.Errorf("Test failed, unexpected Vary headers '%v'.", vary)
+	}
+}
+
+func TestCORSPreflightRejected(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetCORS(AppCORS())
+	router.Use(RequireAuthorization)
+	router.Get("users", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+	router.Put("users", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue))).WithCORS(AdminCORS())
+
+	tests := []struct {
+		origin string
+		method string
+	}{
+		{"https://evil.com", GET},
+		{"https://example.org", GET},
+		{"https://app.example.com", http.MethodDelete},
+		{"https://app.example.com", http.MethodPut},
+	}
+
+	for _, test := range tests {
+		res := ServeRequest(mux, http.MethodOptions, "/v1/users", nil, Preflight(test.origin, test.method, ""))
+
+		if res.Code != http.StatusNoContent || res.Header().Get(badger.CORSAllowOriginHeader) != "" {
+			t.Errorf("Test failed, expected '%s %s' to be rejected got %d '%v'.", test.method, test.origin, res.Code, res.Header())
+		}
+	}
+}
+
+func TestCORSOriginMatching(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetCORS(AppCORS())
+	router.Use(RequireAuthorization)
+	router.Get("users", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+	for _, origin := range []string{"https://api.example.org", "https://a.b.example.org", "HTTPS://APP.EXAMPLE.COM", "http://localhost:3000"} {
+		res := ServeRequest(mux, http.MethodOptions, "/v1/users", nil, Preflight(origin, GET, ""))
+
+		if res.Header().Get(badger.CORSAllowOriginHeader) != origin {
+			t.Errorf("Test failed, expected origin '%s' to be allowed.", origin)
+		}
+	}
+}
+
+func TestCORSRoutePolicy(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetCORS(AppCORS())
+	router.Use(RequireAuthorization)
+	router.Put("users", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue))).WithCORS(AdminCORS())
+
+	res := ServeRequest(mux, http.MethodOptions, "/v1/users", nil, Preflight("https://admin.example.com", http.MethodPut, ""))
+
+	if res.Header().Get(badger.CORSAllowOriginHeader) != "https://admin.example.com" || res.Header().Get(badger.CORSAllowCredentialsHeader) != "true" {
+		t.Errorf("Test failed, expected route policy to apply got '%v'.", res.Header())
+	}
+
+	res = ServeRequest(mux, http.MethodPut, "/v1/users", nil, map[string]string{"Origin": "https://admin.example.com", "Authorization": "token"})
+
+	if res.Header().Get(badger.CORSAllowOriginHeader) != "https://admin.example.com" || res.Header().Get(badger.CORSExposeHeadersHeader) != "" {
+		t.Errorf("Test failed, expected route policy to apply got '%v'.", res.Header())
+	}
+}
+
+func TestCORSActualRequest(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetCORS(AppCORS())
+	router.Use(RequireAuthorization)
+	router.Get("users", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+	res := ServeRequest(mux, GET, "/v1/users", nil, map[string]string{"Origin": "https://app.example.com", "Authorization": "token"})
+
+	if res.Code != http.StatusOK || res.Header().Get(badger.CORSAllowOriginHeader) != "https://app.example.com" || res.Header().Get(badger.CORSExposeHeadersHeader) != "X-Total" {
+		t.Errorf("Test failed, unexpected response %d '%v'.", res.Code, res.Header())
+	}
+
+	res = ServeRequest(mux, GET, "/v1/users", nil, map[string]string{"Origin": "https://evil.com", "Authorization": "token"})
+
+	if res.Code != http.StatusOK || res.Header().Get(badger.CORSAllowOriginHeader) != "" || res.Header().Get("Vary") != "Origin" {
+		t.Errorf("Test failed, unexpected response %d '%v'.", res.Code, res.Header())
+	}
+}
+
+func TestCORSPrivateNetwork(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetCORS(AppCORS())
+	router.Use(RequireAuthorization)
+	router.Get("users", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+	headers := Preflight("https://app.example.com", GET, "")
+	headers[badger.CORSRequestPrivateNetworkHeader] = "true"
+
+	res := ServeRequest(mux, http.MethodOptions, "/v1/users", nil, headers)
+
+	if res.Header().Get(badger.CORSAllowPrivateNetworkHeader) != "true" {
+		t.Errorf("Test failed, expected private network access to be allowed got '%v'.", res.Header())
+	}
+}
+
+func TestCORSPlainOptionsRequest(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetCORS(AppCORS())
+	router.Use(RequireAuthorization)
+	router.Get("users", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+	router.Post("users", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+	router.Put("users", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue))).WithCORS(AdminCORS())
+
+	res := ServeRequest(mux, http.MethodOptions, "/v1/users", nil, map[string]string{"Authorization": "token"})
+
+	if res.Code != http.StatusNoContent || res.Header().Get("Allow") != "GET, OPTIONS, POST, PUT" {
+		t.Errorf("Test failed, expected automatic OPTIONS response got %d '%v'.", res.Code, res.Header())
+	}
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	cors := &badger.CORS{AllowedOrigins: []string{"*"}, AllowedHeaders: []string{"Content-Type"}}
+	handler := cors.Middleware(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusTeapot)
+	}))
+
+	res := ServeRequest(handler, http.MethodOptions, "/", nil, Preflight("https://any.com", POST, "content-type"))
+
+	if res.Code != http.StatusNoContent || res.Header().Get(badger.CORSAllowOriginHeader) != "*" {
+		t.Errorf("Test failed, unexpected preflight response %d '%v'.", res.Code, res.Header())
+	}
+
+	res = ServeRequest(handler, http.MethodOptions, "/", nil, Preflight("https://any.com", POST, "x-other"))
+
+	if res.Header().Get(badger.CORSAllowOriginHeader) != "" {
+		t.Errorf("Test failed, expected header not to be allowed got '%v'.", res.Header())
+	}
+
+	res = ServeRequest(handler, GET, "/", nil, map[string]string{"Origin": "https://any.com"})
+
+	if res.Code != http.StatusTeapot || res.Header().Get(badger.CORSAllowOriginHeader) != "*" {
+		t.Errorf("Test failed, unexpected response %d '%v'.", res.Code, res.Header())
+	}
+}
+
+func TestCORSAnyOriginWithCredentials(t *testing.T) {
+	policy := &badger.CORS{AllowedOrigins: []string{"*"}, AllowCredentials: true}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Test failed, expected '*' origins with credentials to panic.")
+		}
+	}()
+
+	badger.NewMux().AddRouter(RouterBasePath1).SetCORS(policy)
+}
+
+func TestCORSCredentialsOnlyEchoExplicitOrigins(t *testing.T) {
+	policy := &badger.CORS{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true}
+	handler := policy.Middleware(http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+	// Changed after validation, "*" is never echoed with credentials
+	policy.AllowedOrigins = append(policy.AllowedOrigins, "*")
+
+	for _, origin := range []string{"null", "https://evil.com"} {
+		res := ServeRequest(handler, GET, "/", nil, map[string]string{"Origin": origin})
+
+		if res.Header().Get(badger.CORSAllowOriginHeader) != "" || res.Header().Get(badger.CORSAllowCredentialsHeader) != "" {
+			t.Errorf("Test failed, expected origin '%s' not to be allowed got '%v'.", origin, res.Header())
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package badger
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// HTTPError is an error carrying the status code it should be answered with
+type HTTPError struct {
+	Status  int
+	Message string
+	Err     error
+}
+
+// Sentinel HTTP errors, handlers may return them as is or wrap them
+var (
+	ErrBadRequest           = NewHTTPError(http.StatusBadRequest, "")
+	ErrUnauthorized         = NewHTTPError(http.StatusUnauthorized, "")
+	ErrForbidden            = NewHTTPError(http.StatusForbidden, "")
+	ErrNotFound             = NewHTTPError(http.StatusNotFound, "")
+	ErrMethodNotAllowed     = NewHTTPError(http.StatusMethodNotAllowed, "")
+	ErrConflict             = NewHTTPError(http.StatusConflict, "")
+	ErrRequestTooLarge      = NewHTTPError(http.StatusRequestEntityTooLarge, "")
+	ErrUnsupportedMediaType = NewHTTPError(http.StatusUnsupportedMediaType, "")
+	ErrUnprocessableEntity  = NewHTTPError(http.StatusUnprocessableEntity, "")
+	ErrInternalServerError  = NewHTTPError(http.StatusInternalServerError, "")
+	ErrServiceUnavailable   = NewHTTPError(http.StatusServiceUnavailable, "")
+	ErrGatewayTimeout       = NewHTTPError(http.StatusGatewayTimeout, "")
+)
+
+// NewHTTPError returns an HTTPError with the given status, an empty message
+// defaults to the status text
+func NewHTTPError(status int, message string) *HTTPError {
+	if message == "" {
+		message = http.StatusText(status)
+	}
+
+	return &HTTPError{status, message, nil}
+}
+
+func (e *HTTPError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s", e.Message, e.Err.Error())
+	}
+
+	return e.Message
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode returns the status the error should be answered with
+func (e *HTTPError) StatusCode() int {
+	return e.Status
+}
+
+// Wrap returns a copy of the error wrapping err, so errors.Is still matches
+// the sentinel
+func (e *HTTPError) Wrap(err error) *HTTPError {
+	return &HTTPError{e.Status, e.Message, err}
+}
+
+// Is matches HTTP errors with the same status and message
+func (e *HTTPError) Is(target error) bool {
+	t, ok := target.(*HTTPError)
+	return ok && t.Status == e.Status && t.Message == e.Message
+}
+
+// ValidationError reports an invalid request field
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid field '%s': %s", e.Field, e.Message)
+}
+
+// StatusCode returns the status the error should be answered with
+func (e *ValidationError) StatusCode() int {
+	return http.StatusUnprocessableEntity
+}
+
+// ErrorStatus returns the status code an error should be answered with,
+// errors with a StatusCode method decide their own status, ParamError maps
+// to 400, http.MaxBytesError to 413 and any other error to 500
+func ErrorStatus(err error) int {
+	var coder interface{ StatusCode() int }
+	if errors.As(err, &coder) {
+		return coder.StatusCode()
+	}
+
+	var paramerror *ParamError
+	if errors.As(err, &paramerror) {
+		return http.StatusBadRequest
+	}
+
+	var maxbyteserror *http.MaxBytesError
+	if errors.As(err, &maxbyteserror) {
+		return http.StatusRequestEntityTooLarge
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,52 @@
+package badger_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+func TestNewHTTPErrorDefaultsMessage(t *testing.T) {
+	err := badger.NewHTTPError(http.StatusTeapot, "")
+
+	if err.Error() != "I'm a teapot" {
+		t.Errorf("Test failed, expected message '%s' got '%s'.", "I'm a teapot", err.Error())
+	}
+}
+
+func TestHTTPErrorWrapMatchesSentinel(t *testing.T) {
+	cause := errors.New("no such user")
+	err := fmt.Errorf("handler: %w", badger.ErrNotFound.Wrap(cause))
+
+	if !errors.Is(err, badger.ErrNotFound) {
+		t.Error("Test failed, expected error to match ErrNotFound.")
+	}
+
+	if !errors.Is(err, cause) {
+		t.Error("Test failed, expected error to match its cause.")
+	}
+
+	if errors.Is(err, badger.ErrForbidden) {
+		t.Error("Test failed, expected error not to match ErrForbidden.")
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	cases := map[error]int{
+		badger.ErrUnauthorized:                  http.StatusUnauthorized,
+		&badger.ValidationError{Field: "email"}: http.StatusUnprocessableEntity,
+		&badger.ParamError{Key: "id"}:           http.StatusBadRequest,
+		errors.New("unexpected"):                http.StatusInternalServerError,
+		fmt.Errorf("x: %w", badger.ErrConflict): http.StatusConflict,
+		&http.MaxBytesError{Limit: 10}:          http.StatusRequestEntityTooLarge,
+	}
+
+	for err, expected := range cases {
+		if status := badger.ErrorStatus(err); status != expected {
+			t.Errorf("Test failed, expected status %d got %d for '%v'.", expected, status, err)
+		}
+	}
+}
@@ -1,10 +1,9 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"log"
-	"math/rand"
+	"log/slog"
 	"net/http"
 	"os"
 
@@ -26,46 +25,37 @@ func main() {
 	// Create another router group
 	router2 := mux.AddRouter("v2")
 
-	// Adds an handler for route someget
-	router2.Get("someget/:someparam", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+	// Adds an handler for route someget, returned errors are answered by
+	// the mux ErrorHandler
+	router2.Get("someget/:someparam", badger.HandlerE(func(res http.ResponseWriter, req *http.Request) error {
 		routeparams := badger.GetRouteParamsFromRequest(req)
 		someparam, err := routeparams.GetString("someparam")
 
 		if err != nil {
-			fmt.Fprintf(res, "Shoot, param 'someparam' had some problems. Error: %s", err.Error())
+			return err
 		}
 
 		fmt.Fprintf(
 			res,
-			"Hello, I'm router2, also accessed by url %s, and using transaction id %d, and route param %s",
+			"Hello, I'm router2, also accessed by url %s, and using request id %s, and route param %s",
 			req.URL.Path[1:],
-			req.Context().Value("TransactionId"),
+			badger.RequestIDFromContext(req.Context()),
 			someparam,
 		)
+
+		return nil
 	}))
 
 	// Example logger uber-zap(https://github.com/uber-go/zap)
 	logger := log.New(os.Stdout, "", log.Flags())
 
-	// Define a middleware used by router 1
-	router1.Use(func(h http.Handler) http.Handler {
-		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-			logger.Print("Started")
-			h.ServeHTTP(rw, req)
-			logger.Print("Finished")
-		})
-	})
+	// Define a middleware used by router 1, logging every request with
+	// log/slog once it is served
+	router1.Use(badger.NewAccessLog(slog.Default()).Middleware)
 
-	// Define another middleware used by router 1
-	router2.Use(func(h http.Handler) http.Handler {
-		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-			ctx := req.Context()
-			// This number could be a header value
-			ctx = context.WithValue(ctx, "TransactionId", rand.Uint64())
-			req = req.WithContext(ctx)
-			h.ServeHTTP(rw, req)
-		})
-	})
+	// Define another middleware used by router 2, it keeps the incoming
+	// X-Request-ID header or generates a new id
+	router2.Use(badger.NewRequestID().Middleware)
 
 	// Define a middleware used by router 2
 	router2.Use(func(h http.Handler) http.Handler {
@@ -86,10 +76,14 @@ func main() {
 		logger.Print("Sorry, wrong route")
 	})
 
-	// Panic handler
-	mux.PanicHandler = func(rw http.ResponseWriter, req *http.Request, p interface{}) {
-		logger.Panic(fmt.Sprintf("Panicked with '%s'", p.(string)))
+	// Error handler for HandlerE routes
+	mux.ErrorHandler = func(rw http.ResponseWriter, req *http.Request, err error) {
+		logger.Printf("Request failed: %s", err.Error())
+		badger.DefaultErrorHandler(rw, req, err)
 	}
 
+	// Panic handler, recovers any panic value and logs it with its stack
+	mux.PanicHandler = badger.NewRecovery(badger.LogPanicSink(logger)).PanicHandler
+
 	http.ListenAndServe(":8080", mux)
 }
@@ -0,0 +1,81 @@
+package badger
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+type routerFallback struct {
+	prefix  string
+	handler http.Handler
+}
+
+// SetNotFound sets the handler for requests under the router base path
+// matching no route, when several routers apply the one with the longest
+// base path wins. Router middlewares are applied to it.
+func (r *Router) SetNotFound(handler http.Handler) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.notfound = handler
+}
+
+// SetMethodNotAllowed sets the handler for requests under the router base
+// path matching a route but not its method, when several routers apply the
+// one with the longest base path wins. Router middlewares are applied to it.
+func (r *Router) SetMethodNotAllowed(handler http.Handler) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.methodnotallowed = handler
+}
+
+// buildFallbacks returns the handlers chosen by pick for every router,
+// wrapped by the router middlewares and longest base path first
+func buildFallbacks(routers []*Router, pick func(*Router) http.Handler) []routerFallback {
+	fallbacks := []routerFallback{}
+
+	for _, router := range routers {
+		handler := pick(router)
+
+		if handler == nil {
+			continue
+		}
+
+		for _, middleware := range router.middlewares {
+			handler = middleware(handler)
+		}
+
+		fallbacks = append(fallbacks, routerFallback{normalizeRoutePath(router.basepath), handler})
+	}
+
+	sort.SliceStable(fallbacks, func(i, j int) bool {
+		return len(fallbacks[i].prefix) > len(fallbacks[j].prefix)
+	})
+
+	return fallbacks
+}
+
+// fallbackHandler serves the request with the first fallback whose prefix
+// matches the request path, or with def if none does
+func fallbackHandler(fallbacks []routerFallback, def http.Handler) http.Handler {
+	if len(fallbacks) == 0 {
+		return def
+	}
+
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		for _, fallback := range fallbacks {
+			if strings.HasPrefix(req.URL.Path, fallback.prefix) {
+				fallback.handler.ServeHTTP(res, req)
+				return
+			}
+		}
+
+		def.ServeHTTP(res, req)
+	})
+}
+
+func defaultMethodNotAllowed(res http.ResponseWriter, req *http.Request) {
+	http.Error(res, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
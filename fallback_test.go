@@ -0,0 +1,59 @@
+package badger_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+func TestRouterNotFound(t *testing.T) {
+	mux := badger.NewMux()
+	api := mux.AddRouter(RouterBasePath1)
+	admin := mux.AddRouter("v1/admin")
+	web := mux.AddRouter(RouterBasePath2)
+
+	api.SetNotFound(http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, "api")))
+	admin.SetNotFound(http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, "admin")))
+	web.SetNotFound(http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, "web")))
+	mux.NotFound = AssertHandlerFunc(RouteHeaderKey1, "mux")
+
+	AssertRoute(t, mux, GET, "/v1/nowhere", RouteHeaderKey1, "api")
+	AssertRoute(t, mux, GET, "/v1/admin/nowhere", RouteHeaderKey1, "admin")
+	AssertRoute(t, mux, GET, "/v1/administrators", RouteHeaderKey1, "api")
+	AssertRoute(t, mux, GET, "/v2/nowhere", RouteHeaderKey1, "web")
+	AssertRoute(t, mux, GET, "/v3/nowhere", RouteHeaderKey1, "mux")
+}
+
+func TestRouterNotFoundAppliesMiddlewares(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetNotFound(http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+	router.Use(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Add(MiddlewareHeaderKey1, HeadersExpectedValue)
+			h.ServeHTTP(rw, req)
+		})
+	})
+
+	AssertRoute(t, mux, GET, "/v1/nowhere", MiddlewareHeaderKey1, HeadersExpectedValue)
+	AssertRoute(t, mux, GET, "/v2/nowhere", MiddlewareHeaderKey1, "")
+}
+
+func TestRouterMethodNotAllowed(t *testing.T) {
+	mux := badger.NewMux()
+	api := mux.AddRouter(RouterBasePath1)
+	web := mux.AddRouter(RouterBasePath2)
+	api.Get(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+	web.Get(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+	api.SetMethodNotAllowed(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		allowed := badger.GetAllowedMethodsFromRequest(req)
+		res.Header().Add(RouteHeaderKey2, allowed[0])
+	}))
+
+	AssertRoute(t, mux, POST, "/v1/somepath", RouteHeaderKey2, "GET")
+	AssertRoute(t, mux, POST, "/v2/somepath", RouteHeaderKey2, "")
+	AssertRoute(t, mux, POST, "/v2/somepath", "Allow", "GET, OPTIONS")
+}
@@ -1,5 +1,5 @@
 module github.com/hugoluchessi/badger
 
-go 1.14
+go 1.22
 
 require github.com/julienschmidt/httprouter v1.2.0
@@ -0,0 +1,77 @@
+package badger
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+type errorHandlerKey struct{}
+
+// HandlerE is an http.Handler returning an error, it can be given to any
+// Router method taking an http.Handler. Returned errors are handled by the
+// router ErrorHandler, the mux one or DefaultErrorHandler, in that order,
+// unless the response was already started.
+type HandlerE func(http.ResponseWriter, *http.Request) error
+
+// ErrorHandler writes the response for an error returned by a HandlerE
+type ErrorHandler func(http.ResponseWriter, *http.Request, error)
+
+func (h HandlerE) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	rw := trackResponseWriter(res)
+	err := h(rw, req)
+
+	if err == nil || rw.WroteHeader() {
+		return
+	}
+
+	handleError(rw, req, err)
+}
+
+// handleError answers err with the error handler of the request route
+func handleError(res http.ResponseWriter, req *http.Request, err error) {
+	handler, ok := req.Context().Value(errorHandlerKey{}).(ErrorHandler)
+
+	if !ok || handler == nil {
+		handler = DefaultErrorHandler
+	}
+
+	handler(res, req, err)
+}
+
+// DefaultErrorHandler answers with the status given by ErrorStatus, the
+// error message is only written for client errors
+func DefaultErrorHandler(res http.ResponseWriter, req *http.Request, err error) {
+	status := ErrorStatus(err)
+
+	if status >= http.StatusInternalServerError {
+		http.Error(res, http.StatusText(status), status)
+		return
+	}
+
+	var httperror *HTTPError
+	if errors.As(err, &httperror) {
+		http.Error(res, httperror.Message, status)
+		return
+	}
+
+	http.Error(res, err.Error(), status)
+}
+
+// SetErrorHandler sets the handler for errors returned by HandlerE routes of
+// this router, overriding the Mux ErrorHandler
+func (r *Router) SetErrorHandler(handler ErrorHandler) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.errorhandler = handler
+}
+
+// errorHandlerMiddleware makes handler available to HandlerE through the
+// request context
+func errorHandlerMiddleware(handler ErrorHandler, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		req = req.WithContext(context.WithValue(req.Context(), errorHandlerKey{}, handler))
+		h.ServeHTTP(res, req)
+	})
+}
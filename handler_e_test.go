@@ -0,0 +1,114 @@
+package badger_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+func ReturnErrorHandlerE(err error) badger.HandlerE {
+	return func(res http.ResponseWriter, req *http.Request) error {
+		return err
+	}
+}
+
+func TestHandlerEDefaultErrorHandler(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		body   string
+	}{
+		{badger.ErrNotFound, http.StatusNotFound, "Not Found"},
+		{fmt.Errorf("loading user: %w", badger.ErrForbidden.Wrap(errors.New("secret"))), http.StatusForbidden, "Forbidden"},
+		{&badger.ValidationError{Field: "name", Message: "is required"}, http.StatusUnprocessableEntity, "invalid field 'name': is required"},
+		{&badger.ParamError{Key: "id"}, http.StatusBadRequest, "Key 'id' could not be found."},
+		{errors.New("database is down"), http.StatusInternalServerError, "Internal Server Error"},
+	}
+
+	for _, c := range cases {
+		mux := badger.NewMux()
+		router := mux.AddRouter(RouterBasePath1)
+		router.Get(RoutePath1, ReturnErrorHandlerE(c.err))
+		res := ServeRequest(mux, GET, "/v1/somepath", nil, nil)
+
+		if res.Code != c.status {
+			t.Errorf("Test failed, expected status %d got %d for '%v'.", c.status, res.Code, c.err)
+		}
+
+		if body := strings.TrimSpace(res.Body.String()); body != c.body {
+			t.Errorf("Test failed, expected body '%s' got '%s'.", c.body, body)
+		}
+	}
+}
+
+func TestHandlerENoError(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get(RoutePath1, ReturnErrorHandlerE(nil))
+
+	if res := ServeRequest(mux, GET, "/v1/somepath", nil, nil); res.Code != http.StatusOK {
+		t.Errorf("Test failed, expected status %d got %d.", http.StatusOK, res.Code)
+	}
+}
+
+func TestHandlerEStartedResponse(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get(RoutePath1, badger.HandlerE(func(res http.ResponseWriter, req *http.Request) error {
+		res.WriteHeader(http.StatusAccepted)
+		return badger.ErrConflict
+	}))
+
+	if res := ServeRequest(mux, GET, "/v1/somepath", nil, nil); res.Code != http.StatusAccepted {
+		t.Errorf("Test failed, expected status %d got %d.", http.StatusAccepted, res.Code)
+	}
+}
+
+func TestMuxErrorHandler(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get(RoutePath1, ReturnErrorHandlerE(badger.ErrConflict))
+	mux.ErrorHandler = func(res http.ResponseWriter, req *http.Request, err error) {
+		res.Header().Add(RouteHeaderKey1, err.Error())
+		res.WriteHeader(badger.ErrorStatus(err))
+	}
+
+	res := ServeRequest(mux, GET, "/v1/somepath", nil, nil)
+
+	AssertHeader(t, res, RouteHeaderKey1, "Conflict")
+
+	if res.Code != http.StatusConflict {
+		t.Errorf("Test failed, expected status %d got %d.", http.StatusConflict, res.Code)
+	}
+}
+
+func TestRouterErrorHandlerOverridesMux(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get(RoutePath1, ReturnErrorHandlerE(badger.ErrConflict))
+	mux.ErrorHandler = func(res http.ResponseWriter, req *http.Request, err error) {
+		res.Header().Add(RouteHeaderKey1, "mux")
+	}
+	router.SetErrorHandler(func(res http.ResponseWriter, req *http.Request, err error) {
+		res.Header().Add(RouteHeaderKey1, "router")
+	})
+
+	AssertRoute(t, mux, GET, "/v1/somepath", RouteHeaderKey1, "router")
+}
+
+func TestHandlerEWithParamError(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get("users/:id", badger.HandlerE(func(res http.ResponseWriter, req *http.Request) error {
+		_, err := badger.GetRouteParamsFromRequest(req).GetInt("id")
+		return err
+	}))
+
+	if res := ServeRequest(mux, GET, "/v1/users/abc", nil, nil); res.Code != http.StatusBadRequest {
+		t.Errorf("Test failed, expected status %d got %d.", http.StatusBadRequest, res.Code)
+	}
+}
@@ -0,0 +1,79 @@
+package badger
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+// buildHeadRoutes returns a HEAD route for each GET route in routes whose
+// path has no HEAD route
+func buildHeadRoutes(routes []builtRoute) []builtRoute {
+	allowed := allowedMethodsByPath(routes)
+	headroutes := []builtRoute{}
+
+	for _, route := range routes {
+		if route.method != http.MethodGet || slices.Contains(allowed[route.path], http.MethodHead) {
+			continue
+		}
+
+		headroutes = append(headroutes, builtRoute{http.MethodHead, route.path, headHandler(route.handler), route.route})
+	}
+
+	return headroutes
+}
+
+// headHandler runs handler discarding the response body, Content-Length is
+// set to the length of the discarded body unless the handler sets it or
+// flushes the response
+func headHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		hw := &headResponseWriter{res, 0, 0, false}
+		handler.ServeHTTP(hw, req)
+		hw.writeHeader(true)
+	})
+}
+
+type headResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	length      int
+	wroteheader bool
+}
+
+func (w *headResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	w.length += len(b)
+	return len(b), nil
+}
+
+func (w *headResponseWriter) Flush() {
+	w.writeHeader(false)
+
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *headResponseWriter) writeHeader(setlength bool) {
+	if w.wroteheader {
+		return
+	}
+
+	w.wroteheader = true
+
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if setlength && w.Header().Get("Content-Length") == "" {
+		w.Header().Set("Content-Length", strconv.Itoa(w.length))
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+}
@@ -0,0 +1,83 @@
+package badger_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+func TestAutoHeadServesGetRoutes(t *testing.T) {
+	mux := badger.NewMux()
+	mux.AutoHead = true
+	router := mux.AddRouter(RouterBasePath1)
+
+	router.Get(RoutePath1, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add(RouteHeaderKey1, HeadersExpectedValue)
+		res.WriteHeader(http.StatusAccepted)
+		fmt.Fprint(res, "some body")
+	}))
+
+	req, _ := http.NewRequest("HEAD", "/v1/somepath", nil)
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, req)
+
+	if res.Code != http.StatusAccepted {
+		t.Errorf("Test failed, expected status %d got %d.", http.StatusAccepted, res.Code)
+	}
+
+	if res.Body.Len() != 0 {
+		t.Errorf("Test failed, expected empty body got '%s'.", res.Body.String())
+	}
+
+	AssertHeader(t, res, RouteHeaderKey1, HeadersExpectedValue)
+	AssertHeader(t, res, "Content-Length", "9")
+}
+
+func TestAutoHeadKeepsContentLength(t *testing.T) {
+	mux := badger.NewMux()
+	mux.AutoHead = true
+	router := mux.AddRouter(RouterBasePath1)
+
+	router.Get(RoutePath1, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Length", "1024")
+	}))
+
+	AssertRoute(t, mux, "HEAD", "/v1/somepath", "Content-Length", "1024")
+}
+
+func TestAutoHeadExplicitHeadWins(t *testing.T) {
+	mux := badger.NewMux()
+	mux.AutoHead = true
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+	router.Head(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey2, HeadersExpectedValue)))
+
+	AssertRoute(t, mux, "HEAD", "/v1/somepath", RouteHeaderKey1, "")
+	AssertRoute(t, mux, "HEAD", "/v1/somepath", RouteHeaderKey2, HeadersExpectedValue)
+}
+
+func TestAutoHeadDisabled(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+	req, _ := http.NewRequest("HEAD", "/v1/somepath", nil)
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, req)
+
+	if res.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Test failed, expected status %d got %d.", http.StatusMethodNotAllowed, res.Code)
+	}
+}
+
+func TestAutoHeadAllowHeader(t *testing.T) {
+	mux := badger.NewMux()
+	mux.AutoHead = true
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+	AssertRoute(t, mux, "OPTIONS", "/v1/somepath", "Allow", "GET, HEAD, OPTIONS")
+}
@@ -0,0 +1,121 @@
+// Package httprouterbackend provides a badger Backend based on httprouter,
+// kept apart so the badger package itself depends on the standard library
+// only.
+package httprouterbackend
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hugoluchessi/badger"
+	"github.com/julienschmidt/httprouter"
+)
+
+type backend struct {
+	router           *httprouter.Router
+	methods          map[string]bool
+	notFound         http.Handler
+	methodNotAllowed http.Handler
+}
+
+// New returns a badger.Backend based on httprouter
+func New() badger.Backend {
+	b := &backend{httprouter.New(), map[string]bool{}, nil, nil}
+
+	// Answered from Allowed, as by the other backends
+	b.router.HandleMethodNotAllowed = false
+	b.router.HandleOPTIONS = false
+	b.router.NotFound = http.HandlerFunc(b.fallback)
+
+	return b
+}
+
+func (b *backend) Handle(method string, path string, handle badger.BackendHandle) {
+	b.methods[method] = true
+
+	b.router.Handle(method, path, func(res http.ResponseWriter, req *http.Request, rps httprouter.Params) {
+		params := make([]badger.Param, 0, len(rps))
+
+		for _, rp := range rps {
+			params = append(params, badger.Param{Key: rp.Key, Value: rp.Value})
+		}
+
+		handle(res, req, params)
+	})
+}
+
+func (b *backend) SetFallbacks(notFound http.Handler, methodNotAllowed http.Handler) {
+	b.notFound = notFound
+	b.methodNotAllowed = methodNotAllowed
+}
+
+func (b *backend) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	// httprouter only knows MethodAny as a method name of its own
+	if b.methods[badger.MethodAny] {
+		if handle, _, _ := b.router.Lookup(req.Method, req.URL.Path); handle == nil {
+			if handle, params, _ := b.router.Lookup(badger.MethodAny, req.URL.Path); handle != nil {
+				handle(res, req, params)
+				return
+			}
+		}
+	}
+
+	b.router.ServeHTTP(res, req)
+}
+
+func (b *backend) Allowed(req *http.Request) []string {
+	allowed := []string{}
+
+	for method := range b.methods {
+		if method == badger.MethodAny {
+			continue
+		}
+
+		if handle, _, _ := b.router.Lookup(method, req.URL.Path); handle != nil {
+			allowed = append(allowed, method)
+		}
+	}
+
+	sort.Strings(allowed)
+
+	return allowed
+}
+
+// fallback answers the requests httprouter found no handle for, once it
+// tried redirecting them
+func (b *backend) fallback(res http.ResponseWriter, req *http.Request) {
+	if allowed := b.Allowed(req); len(allowed) > 0 {
+		res.Header().Set("Allow", strings.Join(allowed, ", "))
+
+		if b.methodNotAllowed != nil {
+			b.methodNotAllowed.ServeHTTP(res, req)
+			return
+		}
+
+		http.Error(res, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if b.notFound != nil {
+		b.notFound.ServeHTTP(res, req)
+		return
+	}
+
+	http.NotFound(res, req)
+}
+
+// CreateRouteParams converts httprouter.Params to badger.TypedParams
+func CreateRouteParams(rps httprouter.Params) badger.TypedParams {
+	dict := make(map[string]string)
+
+	for _, rp := range rps {
+		if rp.Key == "" {
+			continue
+		}
+
+		dict[rp.Key] = rp.Value
+	}
+
+	return badger.CreateTypedParams(dict)
+}
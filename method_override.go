@@ -0,0 +1,95 @@
+package badger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// MethodOverrideHeader is the header POST requests may use to override
+// their method
+const MethodOverrideHeader = "X-HTTP-Method-Override"
+
+// MethodOverrideField is the url-encoded form field POST requests may use to
+// override their method
+const MethodOverrideField = "_method"
+
+// MethodOverrideFormBytes is how much of url-encoded bodies is read looking
+// for MethodOverrideField. It happens before routing, so before any route
+// MaxBytes applies, and the body is given back untouched to the handler.
+const MethodOverrideFormBytes = 4096
+
+type originalMethodKey struct{}
+
+// overrideMethod changes the method of POST requests asking for one of the
+// allowed methods through MethodOverrideHeader or MethodOverrideField, the
+// header taking precedence
+func overrideMethod(req *http.Request, allowed []string) *http.Request {
+	if req.Method != http.MethodPost {
+		return req
+	}
+
+	method := req.Header.Get(MethodOverrideHeader)
+
+	if method == "" {
+		req, method = peekFormMethod(req)
+	}
+
+	method = strings.ToUpper(strings.TrimSpace(method))
+
+	if method == "" || !slices.Contains(allowed, method) {
+		return req
+	}
+
+	req = req.WithContext(context.WithValue(req.Context(), originalMethodKey{}, req.Method))
+	req.Method = method
+
+	return req
+}
+
+// GetOriginalMethodFromRequest retrieves the method the request was sent
+// with, before any method override
+func GetOriginalMethodFromRequest(req *http.Request) string {
+	if method, ok := req.Context().Value(originalMethodKey{}).(string); ok {
+		return method
+	}
+
+	return req.Method
+}
+
+// peekFormMethod looks for MethodOverrideField in the first
+// MethodOverrideFormBytes of url-encoded bodies, without parsing the whole
+// form. Multipart bodies are not read.
+func peekFormMethod(req *http.Request) (*http.Request, string) {
+	contenttype := req.Header.Get("Content-Type")
+
+	if req.Body == nil || req.Body == http.NoBody || !strings.HasPrefix(contenttype, "application/x-www-form-urlencoded") {
+		return req, ""
+	}
+
+	buf := make([]byte, MethodOverrideFormBytes)
+	n, err := io.ReadFull(req.Body, buf)
+	buf = buf[:n]
+
+	// The caller's request must not see its body replaced
+	peeked := *req
+	peeked.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(buf), req.Body), req.Body}
+
+	// A full buffer may end in the middle of a field
+	if err == nil {
+		buf = buf[:max(bytes.LastIndexByte(buf, '&'), 0)]
+	} else if err != io.EOF && err != io.ErrUnexpectedEOF {
+		return &peeked, ""
+	}
+
+	values, _ := url.ParseQuery(string(buf))
+
+	return &peeked, values.Get(MethodOverrideField)
+}
@@ -0,0 +1,121 @@
+package badger_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+func MethodHandlerFunc(res http.ResponseWriter, req *http.Request) {
+	res.Header().Add(RouteHeaderKey1, req.Method)
+	res.Header().Add(RouteHeaderKey2, badger.GetOriginalMethodFromRequest(req))
+}
+
+func TestMethodOverrideHeader(t *testing.T) {
+	mux := badger.NewMux()
+	mux.MethodOverride = []string{"PUT", "DELETE"}
+	router := mux.AddRouter(RouterBasePath1)
+	router.Match([]string{"POST", "PUT", "DELETE", "PATCH"}, RoutePath1, http.HandlerFunc(MethodHandlerFunc))
+
+	req, _ := http.NewRequest(POST, "/v1/somepath", nil)
+	req.Header.Set(badger.MethodOverrideHeader, "delete")
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, req)
+
+	AssertHeader(t, res, RouteHeaderKey1, "DELETE")
+	AssertHeader(t, res, RouteHeaderKey2, "POST")
+}
+
+func TestMethodOverrideFormField(t *testing.T) {
+	mux := badger.NewMux()
+	mux.MethodOverride = []string{"PUT", "DELETE"}
+	router := mux.AddRouter(RouterBasePath1)
+	router.Match([]string{"POST", "PUT", "DELETE", "PATCH"}, RoutePath1, http.HandlerFunc(MethodHandlerFunc))
+
+	form := url.Values{badger.MethodOverrideField: {"PUT"}}
+	req, _ := http.NewRequest(POST, "/v1/somepath", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, req)
+
+	AssertHeader(t, res, RouteHeaderKey1, "PUT")
+	AssertHeader(t, res, RouteHeaderKey2, "POST")
+}
+
+func TestMethodOverrideFormFieldKeepsBody(t *testing.T) {
+	mux := badger.NewMux()
+	mux.MethodOverride = []string{"PUT"}
+	router := mux.AddRouter(RouterBasePath1)
+	router.Put(RoutePath1, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add(RouteHeaderKey1, req.PostFormValue("name"))
+	}))
+
+	form := url.Values{badger.MethodOverrideField: {"PUT"}, "name": {"badger"}}
+	req, _ := http.NewRequest(POST, "/v1/somepath", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, req)
+
+	AssertHeader(t, res, RouteHeaderKey1, "badger")
+}
+
+func TestMethodOverrideFormFieldOutOfReach(t *testing.T) {
+	mux := badger.NewMux()
+	mux.MethodOverride = []string{"PUT", "DELETE"}
+	router := mux.AddRouter(RouterBasePath1)
+	router.Match([]string{"POST", "PUT", "DELETE", "PATCH"}, RoutePath1, http.HandlerFunc(MethodHandlerFunc))
+
+	body := "padding=" + strings.Repeat("a", badger.MethodOverrideFormBytes) + "&" + badger.MethodOverrideField + "=PUT"
+	req, _ := http.NewRequest(POST, "/v1/somepath", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, req)
+
+	AssertHeader(t, res, RouteHeaderKey1, "POST")
+}
+
+func TestMethodOverrideNotAllowed(t *testing.T) {
+	mux := badger.NewMux()
+	mux.MethodOverride = []string{"PUT", "DELETE"}
+	router := mux.AddRouter(RouterBasePath1)
+	router.Match([]string{"POST", "PUT", "DELETE", "PATCH"}, RoutePath1, http.HandlerFunc(MethodHandlerFunc))
+
+	req, _ := http.NewRequest(POST, "/v1/somepath", nil)
+	req.Header.Set(badger.MethodOverrideHeader, "PATCH")
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, req)
+
+	AssertHeader(t, res, RouteHeaderKey1, "POST")
+}
+
+func TestMethodOverrideOnlyForPost(t *testing.T) {
+	mux := badger.NewMux()
+	mux.MethodOverride = []string{"PUT", "DELETE"}
+	router := mux.AddRouter(RouterBasePath1)
+	router.Match([]string{"POST", "PUT", "DELETE", "PATCH"}, RoutePath1, http.HandlerFunc(MethodHandlerFunc))
+
+	req, _ := http.NewRequest("PATCH", "/v1/somepath", nil)
+	req.Header.Set(badger.MethodOverrideHeader, "DELETE")
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, req)
+
+	AssertHeader(t, res, RouteHeaderKey1, "PATCH")
+	AssertHeader(t, res, RouteHeaderKey2, "PATCH")
+}
+
+func TestMethodOverrideDisabledByDefault(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Match([]string{"POST", "PUT", "DELETE", "PATCH"}, RoutePath1, http.HandlerFunc(MethodHandlerFunc))
+
+	req, _ := http.NewRequest(POST, "/v1/somepath", nil)
+	req.Header.Set(badger.MethodOverrideHeader, "DELETE")
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, req)
+
+	AssertHeader(t, res, RouteHeaderKey1, "POST")
+}
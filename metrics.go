@@ -0,0 +1,305 @@
+package badger
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricsContentType is the content type of the Prometheus text exposition
+// format served by Metrics
+const MetricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultDurationBuckets are the upper bounds, in seconds, of the request
+// duration histogram
+var DefaultDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// DefaultSizeBuckets are the upper bounds, in bytes, of the response size
+// histogram
+var DefaultSizeBuckets = []float64{100, 1000, 10000, 100000, 1000000, 10000000}
+
+// Metrics collects per route request counters, in flight gauges and
+// duration and size histograms, labelled by method, route pattern and
+// status. It serves them in the Prometheus text format as an http.Handler.
+type Metrics struct {
+	// Namespace prefixes every metric name, defaults to "badger"
+	Namespace string
+	// DurationBuckets of the request duration histogram, in seconds,
+	// defaults to DefaultDurationBuckets
+	DurationBuckets []float64
+	// SizeBuckets of the response size histogram, in bytes, defaults to
+	// DefaultSizeBuckets
+	SizeBuckets []float64
+	// Labels adds a label per entry, named after the map key, holding the
+	// route metadata value of the map value, empty when the route has none
+	Labels map[string]interface{}
+
+	lock     sync.Mutex
+	requests map[string]*metricsSeries
+	inflight map[string]*metricsGauge
+}
+
+type metricsLabel struct {
+	name  string
+	value string
+}
+
+type metricsSeries struct {
+	labels   []metricsLabel
+	count    uint64
+	duration *metricsHistogram
+	size     *metricsHistogram
+}
+
+type metricsGauge struct {
+	labels []metricsLabel
+	value  int64
+}
+
+type metricsHistogram struct {
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+// NewMetrics returns a Metrics with the default buckets
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+// Middleware records the requests served by the given handler, Mux.Metrics
+// applies it to every route
+func (m *Metrics) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		route, _ := RouteFromContext(req.Context())
+		labels := m.labels(req, route)
+
+		m.addInFlight(labels, 1)
+
+		start := time.Now()
+		rw := trackResponseWriter(res)
+		completed := false
+
+		defer func() {
+			m.addInFlight(labels, -1)
+
+			status := rw.Status()
+
+			if status == 0 && !completed {
+				status = http.StatusInternalServerError
+			} else if status == 0 {
+				status = http.StatusOK
+			}
+
+			m.observe(labels, status, time.Since(start), rw.Written())
+		}()
+
+		h.ServeHTTP(rw, req)
+		completed = true
+	})
+}
+
+func (m *Metrics) labels(req *http.Request, route RouteInfo) []metricsLabel {
+	labels := []metricsLabel{{"method", req.Method}, {"route", route.Pattern}}
+	names := make([]string, 0, len(m.Labels))
+
+	for name := range m.Labels {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := ""
+
+		if v, ok := route.Metadata[m.Labels[name]]; ok {
+			value = fmt.Sprint(v)
+		}
+
+		labels = append(labels, metricsLabel{name, value})
+	}
+
+	return labels
+}
+
+func (m *Metrics) addInFlight(labels []metricsLabel, delta int64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.inflight == nil {
+		m.inflight = map[string]*metricsGauge{}
+	}
+
+	key := metricsKey(labels)
+	gauge, ok := m.inflight[key]
+
+	if !ok {
+		gauge = &metricsGauge{labels, 0}
+		m.inflight[key] = gauge
+	}
+
+	gauge.value += delta
+}
+
+func (m *Metrics) observe(labels []metricsLabel, status int, duration time.Duration, size int64) {
+	labels = append(labels[:len(labels):len(labels)], metricsLabel{"status", strconv.Itoa(status)})
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.requests == nil {
+		m.requests = map[string]*metricsSeries{}
+	}
+
+	key := metricsKey(labels)
+	series, ok := m.requests[key]
+
+	if !ok {
+		series = &metricsSeries{
+			labels,
+			0,
+			newMetricsHistogram(m.DurationBuckets, DefaultDurationBuckets),
+			newMetricsHistogram(m.SizeBuckets, DefaultSizeBuckets),
+		}
+		m.requests[key] = series
+	}
+
+	series.count++
+	series.duration.observe(duration.Seconds())
+	series.size.observe(float64(size))
+}
+
+// ServeHTTP writes the collected metrics in the Prometheus text format
+func (m *Metrics) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-Type", MetricsContentType)
+	m.WriteTo(res)
+}
+
+// WriteTo writes the collected metrics in the Prometheus text format to w
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+
+	namespace := m.Namespace
+
+	if namespace == "" {
+		namespace = "badger"
+	}
+
+	m.lock.Lock()
+
+	requests := sortedMetrics(m.requests)
+	inflight := sortedMetrics(m.inflight)
+
+	name := namespace + "_http_requests_total"
+	fmt.Fprintf(&b, "# HELP %s Total number of HTTP requests served.\n# TYPE %s counter\n", name, name)
+
+	for _, series := range requests {
+		fmt.Fprintf(&b, "%s%s %d\n", name, formatMetricsLabels(series.labels), series.count)
+	}
+
+	name = namespace + "_http_requests_in_flight"
+	fmt.Fprintf(&b, "# HELP %s Number of HTTP requests being served.\n# TYPE %s gauge\n", name, name)
+
+	for _, gauge := range inflight {
+		fmt.Fprintf(&b, "%s%s %d\n", name, formatMetricsLabels(gauge.labels), gauge.value)
+	}
+
+	name = namespace + "_http_request_duration_seconds"
+	fmt.Fprintf(&b, "# HELP %s Duration of HTTP requests in seconds.\n# TYPE %s histogram\n", name, name)
+
+	for _, series := range requests {
+		series.duration.write(&b, name, series.labels)
+	}
+
+	name = namespace + "_http_response_size_bytes"
+	fmt.Fprintf(&b, "# HELP %s Size of HTTP response bodies in bytes.\n# TYPE %s histogram\n", name, name)
+
+	for _, series := range requests {
+		series.size.write(&b, name, series.labels)
+	}
+
+	m.lock.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func newMetricsHistogram(buckets []float64, defaults []float64) *metricsHistogram {
+	if len(buckets) == 0 {
+		buckets = defaults
+	}
+
+	buckets = append([]float64{}, buckets...)
+	sort.Float64s(buckets)
+
+	return &metricsHistogram{buckets, make([]uint64, len(buckets)), 0, 0}
+}
+
+func (h *metricsHistogram) observe(value float64) {
+	for i, bound := range h.buckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+
+	h.sum += value
+	h.count++
+}
+
+func (h *metricsHistogram) write(b *strings.Builder, name string, labels []metricsLabel) {
+	for i, bound := range h.buckets {
+		le := metricsLabel{"le", strconv.FormatFloat(bound, 'g', -1, 64)}
+		fmt.Fprintf(b, "%s_bucket%s %d\n", name, formatMetricsLabels(append(labels[:len(labels):len(labels)], le)), h.counts[i])
+	}
+
+	inf := metricsLabel{"le", "+Inf"}
+	fmt.Fprintf(b, "%s_bucket%s %d\n", name, formatMetricsLabels(append(labels[:len(labels):len(labels)], inf)), h.count)
+	fmt.Fprintf(b, "%s_sum%s %s\n", name, formatMetricsLabels(labels), strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(b, "%s_count%s %d\n", name, formatMetricsLabels(labels), h.count)
+}
+
+func sortedMetrics[T any](series map[string]T) []T {
+	keys := make([]string, 0, len(series))
+
+	for key := range series {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	sorted := make([]T, 0, len(keys))
+
+	for _, key := range keys {
+		sorted = append(sorted, series[key])
+	}
+
+	return sorted
+}
+
+func metricsKey(labels []metricsLabel) string {
+	parts := make([]string, 0, len(labels))
+
+	for _, label := range labels {
+		parts = append(parts, label.name+"="+label.value)
+	}
+
+	return strings.Join(parts, "\xff")
+}
+
+var metricsLabelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatMetricsLabels(labels []metricsLabel) string {
+	parts := make([]string, 0, len(labels))
+
+	for _, label := range labels {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, label.name, metricsLabelReplacer.Replace(label.value)))
+	}
+
+	return "{" + strings.Join(parts, ",") + "}"
+}
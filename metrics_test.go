@@ -0,0 +1,115 @@
+package badger_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+type TierMetaKey struct{}
+
+func AssertMetricsLines(t *testing.T, body string, lines ...string) {
+	t.Helper()
+
+	for _, line := range lines {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("Test failed, expected line '%s' in:\n%s", line, body)
+		}
+	}
+}
+
+func TestMetricsCounters(t *testing.T) {
+	m := badger.NewMetrics()
+	mux := badger.NewMux()
+	mux.Metrics = m
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get("users/:id", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(res, strings.Repeat("a", 500))
+	}))
+	router.Post("users", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusCreated)
+	}))
+	router.Get("metrics", m)
+
+	for _, r := range []struct{ method, url string }{{GET, "/v1/users/1"}, {GET, "/v1/users/2"}, {POST, "/v1/users"}} {
+		ServeRequest(mux, r.method, r.url, nil, nil)
+	}
+
+	res := ServeRequest(mux, GET, "/v1/metrics", nil, nil)
+
+	if ct := res.Header().Get("Content-Type"); ct != badger.MetricsContentType {
+		t.Errorf("Test failed, unexpected content type '%s'.", ct)
+	}
+
+	AssertMetricsLines(t, res.Body.String(),
+		"# TYPE badger_http_requests_total counter",
+		`badger_http_requests_total{method="GET",route="/v1/users/:id/",status="200"} 2`,
+		`badger_http_requests_total{method="POST",route="/v1/users/",status="201"} 1`,
+		`badger_http_requests_in_flight{method="GET",route="/v1/users/:id/"} 0`,
+		`badger_http_requests_in_flight{method="GET",route="/v1/metrics/"} 1`,
+		"# TYPE badger_http_request_duration_seconds histogram",
+		`badger_http_request_duration_seconds_count{method="GET",route="/v1/users/:id/",status="200"} 2`,
+		`badger_http_response_size_bytes_bucket{method="GET",route="/v1/users/:id/",status="200",le="100"} 0`,
+		`badger_http_response_size_bytes_bucket{method="GET",route="/v1/users/:id/",status="200",le="1000"} 2`,
+		`badger_http_response_size_bytes_bucket{method="GET",route="/v1/users/:id/",status="200",le="+Inf"} 2`,
+		`badger_http_response_size_bytes_sum{method="GET",route="/v1/users/:id/",status="200"} 1000`,
+	)
+}
+
+func TestMetricsCustomBucketsAndLabels(t *testing.T) {
+	m := &badger.Metrics{
+		Namespace:   "api",
+		SizeBuckets: []float64{1000, 10},
+		Labels:      map[string]interface{}{"tier": TierMetaKey{}},
+	}
+	mux := badger.NewMux()
+	mux.Metrics = m
+	router := mux.AddRouter(RouterBasePath1).WithMeta(TierMetaKey{}, "gold")
+	router.Get("users/:id", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(res, strings.Repeat("a", 500))
+	}))
+	router.Get("metrics", m)
+
+	ServeRequest(mux, GET, "/v1/users/1", nil, nil)
+
+	AssertMetricsLines(t, ServeRequest(mux, GET, "/v1/metrics", nil, nil).Body.String(),
+		`api_http_requests_total{method="GET",route="/v1/users/:id/",tier="gold",status="200"} 1`,
+		`api_http_response_size_bytes_bucket{method="GET",route="/v1/users/:id/",tier="gold",status="200",le="10"} 0`,
+		`api_http_response_size_bytes_bucket{method="GET",route="/v1/users/:id/",tier="gold",status="200",le="1000"} 1`,
+	)
+}
+
+func TestMetricsPanic(t *testing.T) {
+	m := badger.NewMetrics()
+	mux := badger.NewMux()
+	mux.Metrics = m
+	mux.PanicHandler = badger.NewRecovery(func(*http.Request, *badger.PanicError) {}).PanicHandler
+	mux.AddRouter(RouterBasePath1).Get("metrics", m)
+	mux.AddRouter("v2").Get("boom", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		panic(ErrBoom)
+	}))
+
+	ServeRequest(mux, GET, "/v2/boom", nil, nil)
+
+	AssertMetricsLines(t, ServeRequest(mux, GET, "/v1/metrics", nil, nil).Body.String(),
+		`badger_http_requests_total{method="GET",route="/v2/boom/",status="500"} 1`,
+		`badger_http_requests_in_flight{method="GET",route="/v2/boom/"} 0`,
+	)
+}
+
+func TestMetricsEscapesLabels(t *testing.T) {
+	m := &badger.Metrics{Labels: map[string]interface{}{"note": TierMetaKey{}}}
+	mux := badger.NewMux()
+	mux.Metrics = m
+	mux.AddRouter("v3").Get("x", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {})).
+		WithMeta(TierMetaKey{}, "a \"b\"\n")
+
+	ServeRequest(mux, GET, "/v3/x", nil, nil)
+
+	AssertMetricsLines(t, ServeRequest(m, GET, "/metrics", nil, nil).Body.String(),
+		`badger_http_requests_total{method="GET",route="/v3/x/",note="a \"b\"\n",status="200"} 1`,
+	)
+}
@@ -0,0 +1,68 @@
+package badger
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strings"
+)
+
+const mountParamKey = "badgermountpath"
+
+type originalPathKey struct{}
+
+type mountPathsKey struct{}
+
+// MountPaths are the paths of a request forwarded to a mounted handler
+type MountPaths struct {
+	// Prefix the handler is mounted at, including the router base path
+	Prefix string
+	// Original is the request path as received by the Mux
+	Original string
+	// Stripped is the request path without the prefix, as seen by the handler
+	Stripped string
+}
+
+// Mount forwards requests of any method to the given handler
+// when their path starts with prefix, stripping the prefix from the request
+// path. Handler can be any http.Handler, including another Mux.
+func (r *Router) Mount(prefix string, handler http.Handler) *Route {
+	mountpath := path.Join(prefix, "*"+mountParamKey)
+	fullprefix := strings.TrimSuffix(normalizeRoutePath(r.basepath, prefix), "/")
+
+	mounted := http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		original := getOriginalPathFromRequest(req)
+		stripped, _ := GetRouteParamsFromRequest(req).GetString(mountParamKey)
+
+		// Undo the trailing slash added by Mux
+		if !strings.HasSuffix(original, "/") && stripped != "/" {
+			stripped = strings.TrimSuffix(stripped, "/")
+		}
+
+		paths := MountPaths{fullprefix, original, stripped}
+		req = req.WithContext(context.WithValue(req.Context(), mountPathsKey{}, paths))
+		u := *req.URL
+		u.Path = stripped
+		u.RawPath = ""
+		req.URL = &u
+
+		handler.ServeHTTP(res, req)
+	})
+
+	return r.Match([]string{MethodAny}, mountpath, mounted)
+}
+
+// GetMountPathsFromRequest retrieves the paths of a request forwarded to a
+// mounted handler, ok is false if the request was not forwarded by Mount
+func GetMountPathsFromRequest(req *http.Request) (MountPaths, bool) {
+	paths, ok := req.Context().Value(mountPathsKey{}).(MountPaths)
+	return paths, ok
+}
+
+func getOriginalPathFromRequest(req *http.Request) string {
+	if original, ok := req.Context().Value(originalPathKey{}).(string); ok {
+		return original
+	}
+
+	return req.URL.Path
+}
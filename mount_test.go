@@ -0,0 +1,81 @@
+package badger_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+func TestMountStripsPrefix(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+
+	legacy := http.NewServeMux()
+	legacy.HandleFunc("/pprof/heap", AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue))
+	router.Mount("debug", legacy)
+
+	AssertRoute(t, mux, GET, "/v1/debug/pprof/heap", RouteHeaderKey1, HeadersExpectedValue)
+	AssertRoute(t, mux, POST, "/v1/debug/pprof/heap", RouteHeaderKey1, HeadersExpectedValue)
+	AssertRoute(t, mux, "PROPFIND", "/v1/debug/pprof/heap", RouteHeaderKey1, HeadersExpectedValue)
+}
+
+func TestMountExposesPaths(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+
+	router.Mount("debug", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		paths, ok := badger.GetMountPathsFromRequest(req)
+
+		if !ok {
+			t.Error("Test failed, mount paths must be found.")
+		}
+
+		if paths.Prefix != "/v1/debug" || paths.Original != "/v1/debug/vars" || paths.Stripped != "/vars" {
+			t.Errorf("Test failed, unexpected mount paths '%+v'.", paths)
+		}
+
+		if req.URL.Path != "/vars" {
+			t.Errorf("Test failed, expected path '/vars' got '%s'.", req.URL.Path)
+		}
+	}))
+
+	req, _ := http.NewRequest(GET, "/v1/debug/vars", nil)
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, req)
+}
+
+func TestMountAppliesRouterMiddlewares(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Mount("debug", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+	router.Use(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Add(MiddlewareHeaderKey1, HeadersExpectedValue)
+			h.ServeHTTP(rw, req)
+		})
+	})
+
+	AssertRoute(t, mux, GET, "/v1/debug", MiddlewareHeaderKey1, HeadersExpectedValue)
+	AssertRoute(t, mux, GET, "/v1/debug", RouteHeaderKey1, HeadersExpectedValue)
+}
+
+func TestMountAnotherMux(t *testing.T) {
+	inner := badger.NewMux()
+	inner.AddRouter("users").Get(":id", ParamHandlerFunc(RouteHeaderKey1, "id"))
+
+	mux := badger.NewMux()
+	mux.AddRouter(RouterBasePath1).Mount("legacy", inner)
+
+	AssertRoute(t, mux, GET, "/v1/legacy/users/42", RouteHeaderKey1, "42")
+}
+
+func TestGetMountPathsFromRequestNotMounted(t *testing.T) {
+	req, _ := http.NewRequest(GET, "nowhere", nil)
+
+	if _, ok := badger.GetMountPathsFromRequest(req); ok {
+		t.Error("Test failed, mount paths must not be found.")
+	}
+}
@@ -7,26 +7,43 @@ import (
 	"path"
 	"strings"
 	"sync"
-
-	"github.com/julienschmidt/httprouter"
 )
 
-type handleFunc func(http.Handler) httprouter.Handle
-
 // Mux is the main structure to define you routes, it has helper functions
 // to build all your web routing and middleware chain.
 type Mux struct {
 	routers          []*Router
-	mainrouter       *httprouter.Router
+	mainrouter       Backend
 	lock             sync.RWMutex
+	Backend          Backend
 	NotFound         http.HandlerFunc
 	MethodNotAllowed http.HandlerFunc
 	PanicHandler     func(http.ResponseWriter, *http.Request, interface{})
+	// AutoHead serves HEAD requests for every GET route with no Head route,
+	// running the GET handler and discarding the response body
+	AutoHead bool
+	// MethodOverride lists the methods POST requests may be overridden to
+	// using the X-HTTP-Method-Override header or the _method form field,
+	// overriding is disabled when empty
+	MethodOverride []string
+	// ErrorHandler handles errors returned by HandlerE routes of routers with
+	// no ErrorHandler, defaults to DefaultErrorHandler
+	ErrorHandler ErrorHandler
+	// ProblemDetails answers with application/problem+json the default
+	// NotFound, MethodNotAllowed and ErrorHandler responses, and the panic
+	// responses of Recovery values with no Respond
+	ProblemDetails bool
+	// Metrics records metrics for every route when set, it can be mounted
+	// to expose them
+	Metrics *Metrics
+	// Timing times every middleware and final handler of the routes when
+	// set, sending a Server-Timing header and logging slow requests
+	Timing *Timing
 }
 
 // NewMux returns a pointer to a newly created mux
 func NewMux() *Mux {
-	return &Mux{[]*Router{}, nil, sync.RWMutex{}, nil, nil, nil}
+	return &Mux{[]*Router{}, nil, sync.RWMutex{}, nil, nil, nil, nil, false, nil, nil, false, nil, nil}
 }
 
 // AddRouter creates a new router with the given base route and returns it
@@ -41,11 +58,35 @@ func (mux *Mux) AddRouter(path string) *Router {
 }
 
 func (mux *Mux) ServeHTTP(res http.ResponseWriter, req *http.Request) {
-	req.URL.Path = normalizeRoutePath(req.URL.Path)
+	// Recovery picks its default response from it
+	if mux.ProblemDetails {
+		req = req.WithContext(context.WithValue(req.Context(), problemDetailsKey{}, true))
+	}
+
+	if mux.PanicHandler != nil {
+		res = trackResponseWriter(res)
+		defer mux.recover(res, req)
+	}
+
+	if len(mux.MethodOverride) > 0 {
+		req = overrideMethod(req, mux.MethodOverride)
+	}
+
+	if p := normalizeRoutePath(req.URL.Path); p != req.URL.Path {
+		req = req.WithContext(context.WithValue(req.Context(), originalPathKey{}, req.URL.Path))
+		req.URL.Path = p
+	}
+
 	mux.getMainRouterInstance().ServeHTTP(res, req)
 }
 
-func (mux *Mux) getMainRouterInstance() *httprouter.Router {
+func (mux *Mux) recover(res http.ResponseWriter, req *http.Request) {
+	if p := recover(); p != nil {
+		mux.PanicHandler(res, req, p)
+	}
+}
+
+func (mux *Mux) getMainRouterInstance() Backend {
 	if mux.mainrouter == nil {
 		mux.createMainRouterInstance()
 	}
@@ -57,61 +98,175 @@ func (mux *Mux) createMainRouterInstance() {
 	mux.lock.Lock()
 	defer mux.lock.Unlock()
 
-	mux.mainrouter = httprouter.New()
+	mux.mainrouter = mux.Backend
+
+	if mux.mainrouter == nil {
+		mux.mainrouter = NewTreeBackend()
+	}
+
+	var notFound, methodNotAllowed http.Handler
+	notFound = http.NotFoundHandler()
+	methodNotAllowed = http.HandlerFunc(defaultMethodNotAllowed)
+
+	if mux.ProblemDetails {
+		notFound = http.HandlerFunc(problemNotFound)
+		methodNotAllowed = http.HandlerFunc(problemMethodNotAllowed)
+	}
 
 	if mux.NotFound != nil {
-		mux.mainrouter.NotFound = mux.NotFound
+		notFound = mux.NotFound
 	}
 
 	if mux.MethodNotAllowed != nil {
-		mux.mainrouter.MethodNotAllowed = mux.MethodNotAllowed
+		methodNotAllowed = mux.MethodNotAllowed
 	}
 
-	if mux.PanicHandler != nil {
-		mux.mainrouter.PanicHandler = mux.PanicHandler
+	notFound = fallbackHandler(buildFallbacks(mux.routers, func(r *Router) http.Handler {
+		return r.notfound
+	}), notFound)
+
+	methodNotAllowed = fallbackHandler(buildFallbacks(mux.routers, func(r *Router) http.Handler {
+		return r.methodnotallowed
+	}), methodNotAllowed)
+
+	mux.mainrouter.SetFallbacks(notFound, methodNotAllowedHandler(methodNotAllowed))
+
+	for _, route := range mux.buildRoutes() {
+		handle := func(h http.Handler, info *RouteInfo) BackendHandle {
+			return func(res http.ResponseWriter, req *http.Request, rps []Param) {
+				typed := createRouteParams(rps)
+				ctx := req.Context()
+				ctx = context.WithValue(ctx, RouteParamsKey, typed)
+				ctx = context.WithValue(ctx, routeInfoKey{}, info)
+				req = req.WithContext(ctx)
+
+				// Also expose params through the standard library
+				for _, rp := range rps {
+					if rp.Key != "" {
+						req.SetPathValue(rp.Key, rp.Value)
+					}
+				}
+
+				h.ServeHTTP(res, req)
+			}
+		}(route.handler, newRouteInfo(route.method, route.route))
+
+		for _, path := range expandOptionalPath(route.path) {
+			mux.mainrouter.Handle(route.method, path, handle)
+		}
 	}
+}
+
+// Routes returns the routes registered in every router
+func (mux *Mux) Routes() []*Route {
+	mux.lock.RLock()
+	defer mux.lock.RUnlock()
+
+	routes := []*Route{}
 
 	for _, router := range mux.routers {
-		routerroutes := router.buildRoutes()
-
-		for _, route := range routerroutes {
-			mux.mainrouter.Handle(
-				route.method,
-				route.path,
-				// FIXME: Ignore params for now
-				handleFunc(func(h http.Handler) httprouter.Handle {
-					return func(res http.ResponseWriter, req *http.Request, rps httprouter.Params) {
-						typed := CreateRouteParams(rps)
-						ctx := req.Context()
-						ctx = context.WithValue(ctx, RouteParamsKey, typed)
-						req = req.WithContext(ctx)
-
-						h.ServeHTTP(res, req)
-					}
-				})(route.handler),
-			)
+		routes = append(routes, router.Routes()...)
+	}
+
+	return routes
+}
+
+func (mux *Mux) buildRoutes() []builtRoute {
+	routes := []builtRoute{}
+	errorhandler := mux.ErrorHandler
+
+	if errorhandler == nil && mux.ProblemDetails {
+		errorhandler = ProblemErrorHandler
+	}
+
+	for _, router := range mux.routers {
+		routes = append(routes, router.buildRoutes(errorhandler, mux.Timing)...)
+	}
+
+	// HEAD routes discard the compressed GET body, keeping its headers
+	applyCompression(routes)
+
+	if mux.AutoHead {
+		routes = append(routes, buildHeadRoutes(routes)...)
+	}
+
+	allowed := allowedMethodsByPath(routes)
+	done := map[string]bool{}
+	optionsroutes := []builtRoute{}
+
+	for _, router := range mux.routers {
+		optionsroutes = append(optionsroutes, router.buildOptionsRoutes(allowed, done, mux.mainrouter)...)
+	}
+
+	applyCompression(optionsroutes)
+	routes = append(routes, optionsroutes...)
+	applyCORS(routes, allowed)
+
+	if mux.Metrics != nil {
+		for i := range routes {
+			routes[i].handler = mux.Metrics.Middleware(routes[i].handler)
 		}
 	}
+
+	return routes
 }
 
-func (r *Router) buildRoutes() []Route {
-	builtroutes := make([]Route, 0)
+func (r *Router) buildRoutes(errorhandler ErrorHandler, timing *Timing) []builtRoute {
+	builtroutes := make([]builtRoute, 0)
+
+	if r.errorhandler != nil {
+		errorhandler = r.errorhandler
+	}
 
 	for _, route := range r.routes {
-		// Ensure path starts and ends with /
-		p := normalizeRoutePath(r.basepath, route.path)
-		builtroute := Route{route.method, p, route.handler}
+		handler := route.handler
+
+		// Only the handler runs past the deadline, middlewares see the
+		// timeout response
+		if timeout, _ := timeoutPolicyKey.FromRoute(route); timeout != nil && timeout.Duration > 0 {
+			handler = timeout.handler(handler)
+		}
+
+		if timing != nil {
+			handler = timing.instrument(handler, r.middlewares)
+		} else {
+			for _, middleware := range r.middlewares {
+				handler = middleware(handler)
+			}
+		}
+
+		if n, _ := MaxBytesKey.FromRoute(route); n > 0 {
+			handler = maxBytesHandler(n, handler)
+		}
 
-		for _, middleware := range r.middlewares {
-			builtroute.handler = middleware(builtroute.handler)
+		if errorhandler != nil {
+			handler = errorHandlerMiddleware(errorhandler, handler)
 		}
 
-		builtroutes = append(builtroutes, builtroute)
+		if timing != nil {
+			handler = timing.start(handler)
+		}
+
+		for _, method := range route.methods {
+			builtroutes = append(builtroutes, builtRoute{method, route.pattern, handler, route})
+		}
 	}
 
 	return builtroutes
 }
 
+func buildRoutePath(basepath string, routepath string) string {
+	// Ensure path starts and ends with /
+	p := normalizeRoutePath(basepath, routepath)
+
+	// Catch-all params must be the last thing in the path
+	if strings.HasPrefix(path.Base(p), "*") {
+		p = strings.TrimSuffix(p, "/")
+	}
+
+	return p
+}
+
 func normalizeRoutePath(p ...string) string {
 	rp := strings.Join(p, "/")
 	return fmt.Sprintf("%s/", path.Join("/", rp))
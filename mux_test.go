@@ -388,3 +388,19 @@ func TestBuildRoutesWitPanicHandler(t *testing.T) {
 
 	AssertHeader(t, res, headerkey, headervalue)
 }
+
+func TestServeHTTPWithParamsSetsPathValue(t *testing.T) {
+	mux := badger.NewMux()
+	routepath := path.Join("/", RouterBasePath1, RoutePath1)
+	router := mux.AddRouter("")
+
+	router.Get(path.Join(routepath, "/:testparam"), http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Add(RouteHeaderKey1, r.PathValue("testparam"))
+	}))
+
+	req, _ := http.NewRequest(GET, path.Join(routepath, "/test"), nil)
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, req)
+
+	AssertHeader(t, res, RouteHeaderKey1, "test")
+}
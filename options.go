@@ -0,0 +1,129 @@
+package badger
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+)
+
+type allowedMethodsKey struct{}
+
+// DefaultOptionsHandler answers automatic OPTIONS requests with no content,
+// the Allow header is already set when it is called
+var DefaultOptionsHandler http.Handler = http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+	res.WriteHeader(http.StatusNoContent)
+})
+
+// SetOptionsHandler sets the handler answering OPTIONS requests for paths of
+// this router which have no Options route, after setting the Allow header
+// with the methods matching the request path, as 405 responses do. Router middlewares are applied
+// to it. A nil handler disables automatic OPTIONS responses.
+func (r *Router) SetOptionsHandler(handler http.Handler) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.optionshandler = handler
+}
+
+// GetAllowedMethodsFromRequest retrieves the methods allowed for the request
+// path, it is set for the Mux MethodNotAllowed handler
+func GetAllowedMethodsFromRequest(req *http.Request) []string {
+	allowed, _ := req.Context().Value(allowedMethodsKey{}).([]string)
+	return allowed
+}
+
+// buildOptionsRoutes returns an automatic OPTIONS route for each path of the
+// router with no OPTIONS method allowed, skipping paths present in done. The
+// Allow header is looked up in backend for every request.
+func (r *Router) buildOptionsRoutes(allowed map[string][]string, done map[string]bool, backend Backend) []builtRoute {
+	if r.optionshandler == nil {
+		return []builtRoute{}
+	}
+
+	optionsroutes := []builtRoute{}
+
+	for _, route := range r.buildPaths() {
+		path := route.pattern
+
+		if done[path] {
+			continue
+		}
+
+		done[path] = true
+		methods := allowed[path]
+
+		// MethodAny routes answer OPTIONS themselves
+		if slices.Contains(methods, http.MethodOptions) || slices.Contains(methods, MethodAny) {
+			continue
+		}
+
+		handler := allowHandler(backend, r.optionshandler)
+
+		for _, middleware := range r.middlewares {
+			handler = middleware(handler)
+		}
+
+		// Described by the first route of the path, its pattern already
+		// includes the base path
+		optionsroute := &Route{
+			[]string{http.MethodOptions}, route.path, route.pattern, route.basepath, route.name,
+			maps.Clone(route.metadata), slices.Clone(route.tags), r, r.optionshandler,
+		}
+		optionsroutes = append(optionsroutes, builtRoute{http.MethodOptions, path, handler, optionsroute})
+	}
+
+	return optionsroutes
+}
+
+// buildPaths returns the first route registered for each distinct
+// normalized path of the router
+func (r *Router) buildPaths() []*Route {
+	paths := []*Route{}
+	seen := map[string]bool{}
+
+	for _, route := range r.routes {
+		if !seen[route.pattern] {
+			seen[route.pattern] = true
+			paths = append(paths, route)
+		}
+	}
+
+	return paths
+}
+
+func allowHandler(backend Backend, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Allow", strings.Join(backend.Allowed(req), ", "))
+		handler.ServeHTTP(res, req)
+	})
+}
+
+// methodNotAllowedHandler exposes the Allow header set by the backend to
+// handler through the request context
+func methodNotAllowedHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		allowed := strings.Split(res.Header().Get("Allow"), ", ")
+		req = req.WithContext(context.WithValue(req.Context(), allowedMethodsKey{}, allowed))
+
+		handler.ServeHTTP(res, req)
+	})
+}
+
+func allowedMethodsByPath(routes []builtRoute) map[string][]string {
+	allowed := map[string][]string{}
+
+	for _, route := range routes {
+		if !slices.Contains(allowed[route.path], route.method) {
+			allowed[route.path] = append(allowed[route.path], route.method)
+		}
+	}
+
+	for _, methods := range allowed {
+		sort.Strings(methods)
+	}
+
+	return allowed
+}
@@ -0,0 +1,132 @@
+package badger_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+func TestAutomaticOptions(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Post(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+	router.Get(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+	req, _ := http.NewRequest("OPTIONS", "/v1/somepath", nil)
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, req)
+
+	if res.Code != http.StatusNoContent {
+		t.Errorf("Test failed, expected status %d got %d.", http.StatusNoContent, res.Code)
+	}
+
+	AssertHeader(t, res, "Allow", "GET, OPTIONS, POST")
+}
+
+func TestAutomaticOptionsAppliesMiddlewares(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+	router.Use(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Add(MiddlewareHeaderKey1, HeadersExpectedValue)
+			h.ServeHTTP(rw, req)
+		})
+	})
+
+	AssertRoute(t, mux, "OPTIONS", "/v1/somepath", MiddlewareHeaderKey1, HeadersExpectedValue)
+}
+
+func TestExplicitOptionsRouteWins(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+	router.Options(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey2, HeadersExpectedValue)))
+
+	AssertRoute(t, mux, "OPTIONS", "/v1/somepath", RouteHeaderKey2, HeadersExpectedValue)
+}
+
+func TestSetOptionsHandler(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+	router.SetOptionsHandler(http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey2, HeadersExpectedValue)))
+
+	AssertRoute(t, mux, "OPTIONS", "/v1/somepath", RouteHeaderKey2, HeadersExpectedValue)
+}
+
+func TestSetOptionsHandlerNilDisables(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+	router.SetOptionsHandler(nil)
+
+	req, _ := http.NewRequest("OPTIONS", "/v1/somepath", nil)
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, req)
+
+	if res.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Test failed, expected status %d got %d.", http.StatusMethodNotAllowed, res.Code)
+	}
+
+	AssertHeader(t, res, "Allow", "GET")
+}
+
+func TestMethodNotAllowedReceivesAllowedMethods(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+	router.Put(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+	mux.MethodNotAllowed = func(res http.ResponseWriter, req *http.Request) {
+		allowed := badger.GetAllowedMethodsFromRequest(req)
+		res.Header().Add(RouteHeaderKey2, strings.Join(allowed, " "))
+	}
+
+	AssertRoute(t, mux, "DELETE", "/v1/somepath", RouteHeaderKey2, "GET OPTIONS PUT")
+}
+
+func TestAutomaticOptionsRouteInfo(t *testing.T) {
+	var info badger.RouteInfo
+
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get("users/:id", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue))).
+		Named("user").Tagged("public")
+	router.SetOptionsHandler(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		info, _ = badger.RouteFromContext(req.Context())
+	}))
+
+	req, _ := http.NewRequest("OPTIONS", "/v1/users/42", nil)
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+
+	if info.Method != "OPTIONS" || info.Pattern != "/v1/users/:id/" || info.BasePath != "/v1/" || info.Name != "user" || !info.HasTag("public") {
+		t.Errorf("Test failed, unexpected route info '%+v'.", info)
+	}
+}
+
+func TestAutomaticOptionsAllowMatchesMethodNotAllowed(t *testing.T) {
+	for name, backend := range map[string]func() badger.Backend{"tree": badger.NewTreeBackend, "servemux": badger.NewServeMuxBackend} {
+		mux := badger.NewMux()
+		mux.Backend = backend()
+		router := mux.AddRouter(RouterBasePath1)
+		router.Get("users/me", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+		router.Delete("users/:id", http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+		req, _ := http.NewRequest("OPTIONS", "/v1/users/me", nil)
+		options := httptest.NewRecorder()
+		mux.ServeHTTP(options, req)
+
+		req, _ = http.NewRequest("PUT", "/v1/users/me", nil)
+		notallowed := httptest.NewRecorder()
+		mux.ServeHTTP(notallowed, req)
+
+		if allow := options.Header().Get("Allow"); allow != "DELETE, GET, OPTIONS" || allow != notallowed.Header().Get("Allow") {
+			t.Errorf("Test failed, %s expected matching Allow headers got '%s' and '%s'.", name, allow, notallowed.Header().Get("Allow"))
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package badger
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ProblemContentType is the media type of problem details responses
+const ProblemContentType = "application/problem+json"
+
+// ProblemInstance returns the instance member of problems written with no
+// instance, it defaults to the request id set by RequestID or to a random
+// urn:uuid identifying the request
+var ProblemInstance = func(req *http.Request) string {
+	if id := RequestIDFromContext(req.Context()); id != "" {
+		return id
+	}
+
+	return "urn:uuid:" + newUUID()
+}
+
+// Problem is an RFC 9457 problem details object, it is also an error so
+// HandlerE can return it
+type Problem struct {
+	Type     string
+	Title    string
+	Status   int
+	Detail   string
+	Instance string
+	// Extensions are additional members written alongside the standard ones
+	Extensions map[string]interface{}
+}
+
+// NewProblem returns a problem for the given status, titled after it
+func NewProblem(status int, detail string) *Problem {
+	return &Problem{"about:blank", http.StatusText(status), status, detail, "", map[string]interface{}{}}
+}
+
+// With sets an extension member and returns the problem
+func (p *Problem) With(key string, value interface{}) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = map[string]interface{}{}
+	}
+
+	p.Extensions[key] = value
+	return p
+}
+
+func (p *Problem) Error() string {
+	if p.Detail == "" {
+		return p.Title
+	}
+
+	return fmt.Sprintf("%s: %s", p.Title, p.Detail)
+}
+
+// StatusCode returns the status the problem should be answered with
+func (p *Problem) StatusCode() int {
+	return p.Status
+}
+
+// MarshalJSON writes the standard members and the extensions at the same
+// level, standard members taking precedence
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]interface{}, len(p.Extensions)+5)
+
+	for key, value := range p.Extensions {
+		members[key] = value
+	}
+
+	standard := map[string]interface{}{
+		"type":     p.Type,
+		"title":    p.Title,
+		"detail":   p.Detail,
+		"instance": p.Instance,
+	}
+
+	for key, value := range standard {
+		if value != "" {
+			members[key] = value
+		}
+	}
+
+	if p.Status != 0 {
+		members["status"] = p.Status
+	}
+
+	return json.Marshal(members)
+}
+
+// WriteProblem writes p as an application/problem+json response, setting
+// its instance with ProblemInstance when empty
+func WriteProblem(res http.ResponseWriter, req *http.Request, p *Problem) {
+	problem := *p
+
+	if problem.Instance == "" {
+		problem.Instance = ProblemInstance(req)
+	}
+
+	if problem.Status == 0 {
+		problem.Status = http.StatusInternalServerError
+	}
+
+	body, err := json.Marshal(&problem)
+
+	if err != nil {
+		http.Error(res, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	res.Header().Set("Content-Type", ProblemContentType)
+	res.Header().Set("X-Content-Type-Options", "nosniff")
+	res.WriteHeader(problem.Status)
+	res.Write(body)
+}
+
+// ProblemErrorHandler is an ErrorHandler answering with problem details,
+// problems returned by handlers are written as is, other errors are
+// converted with the status given by ErrorStatus and, for client errors
+// only, their message as detail
+func ProblemErrorHandler(res http.ResponseWriter, req *http.Request, err error) {
+	var problem *Problem
+	if errors.As(err, &problem) {
+		WriteProblem(res, req, problem)
+		return
+	}
+
+	status := ErrorStatus(err)
+	detail := ""
+
+	if status < http.StatusInternalServerError {
+		detail = err.Error()
+
+		var httperror *HTTPError
+		if errors.As(err, &httperror) {
+			detail = httperror.Message
+		}
+	}
+
+	WriteProblem(res, req, NewProblem(status, detail))
+}
+
+type problemDetailsKey struct{}
+
+// problemDetailsEnabled reports whether the request is served by a Mux
+// with ProblemDetails enabled
+func problemDetailsEnabled(req *http.Request) bool {
+	enabled, _ := req.Context().Value(problemDetailsKey{}).(bool)
+	return enabled
+}
+
+// ProblemPanicResponse is a Recovery response answering with a 500 problem
+func ProblemPanicResponse(res http.ResponseWriter, req *http.Request, err *PanicError) {
+	WriteProblem(res, req, NewProblem(http.StatusInternalServerError, ""))
+}
+
+func problemNotFound(res http.ResponseWriter, req *http.Request) {
+	WriteProblem(res, req, NewProblem(http.StatusNotFound, ""))
+}
+
+func problemMethodNotAllowed(res http.ResponseWriter, req *http.Request) {
+	problem := NewProblem(http.StatusMethodNotAllowed, "")
+	WriteProblem(res, req, problem.With("allowed", GetAllowedMethodsFromRequest(req)))
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,144 @@
+package badger_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+func DecodeProblem(t *testing.T, res *httptest.ResponseRecorder) map[string]interface{} {
+	AssertHeader(t, res, "Content-Type", badger.ProblemContentType)
+
+	members := map[string]interface{}{}
+
+	if err := json.Unmarshal(res.Body.Bytes(), &members); err != nil {
+		t.Fatalf("Test failed, invalid problem body '%s'.", res.Body.String())
+	}
+
+	return members
+}
+
+func TestProblemMarshalJSON(t *testing.T) {
+	problem := badger.NewProblem(http.StatusConflict, "already exists").With("resource", "user")
+	problem.Instance = "/users/1"
+
+	body, _ := json.Marshal(problem)
+	expected := `{"detail":"already exists","instance":"/users/1","resource":"user","status":409,"title":"Conflict","type":"about:blank"}`
+
+	if string(body) != expected {
+		t.Errorf("Test failed, expected '%s' got '%s'.", expected, string(body))
+	}
+}
+
+func TestWriteProblemSetsInstance(t *testing.T) {
+	req, _ := http.NewRequest(GET, "/v1/somepath", nil)
+	res := httptest.NewRecorder()
+
+	badger.WriteProblem(res, req, badger.NewProblem(http.StatusBadRequest, ""))
+	members := DecodeProblem(t, res)
+
+	if res.Code != http.StatusBadRequest {
+		t.Errorf("Test failed, expected status %d got %d.", http.StatusBadRequest, res.Code)
+	}
+
+	if instance, _ := members["instance"].(string); !strings.HasPrefix(instance, "urn:uuid:") {
+		t.Errorf("Test failed, expected an urn:uuid instance got '%s'.", instance)
+	}
+}
+
+func TestProblemDetailsNotFound(t *testing.T) {
+	mux := badger.NewMux()
+	mux.ProblemDetails = true
+	mux.AddRouter(RouterBasePath1).Get(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+	req, _ := http.NewRequest(GET, "/v1/nowhere", nil)
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, req)
+
+	if members := DecodeProblem(t, res); members["status"] != float64(http.StatusNotFound) {
+		t.Errorf("Test failed, unexpected problem '%v'.", members)
+	}
+}
+
+func TestProblemDetailsMethodNotAllowed(t *testing.T) {
+	mux := badger.NewMux()
+	mux.ProblemDetails = true
+	mux.AddRouter(RouterBasePath1).Get(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+	req, _ := http.NewRequest(POST, "/v1/somepath", nil)
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, req)
+
+	members := DecodeProblem(t, res)
+
+	if members["status"] != float64(http.StatusMethodNotAllowed) {
+		t.Errorf("Test failed, unexpected problem '%v'.", members)
+	}
+
+	if allowed, _ := members["allowed"].([]interface{}); len(allowed) != 2 {
+		t.Errorf("Test failed, expected allowed methods got '%v'.", members["allowed"])
+	}
+}
+
+func TestProblemDetailsErrorHandler(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		detail interface{}
+	}{
+		{badger.NewProblem(http.StatusUnprocessableEntity, "bad email").With("field", "email"), http.StatusUnprocessableEntity, "bad email"},
+		{badger.ErrUnsupportedMediaType, http.StatusUnsupportedMediaType, "Unsupported Media Type"},
+		{errors.New("secret"), http.StatusInternalServerError, nil},
+	}
+
+	for _, c := range cases {
+		mux := badger.NewMux()
+		mux.ProblemDetails = true
+		router := mux.AddRouter(RouterBasePath1)
+		router.Get(RoutePath1, ReturnErrorHandlerE(c.err))
+
+		res := ServeRequest(mux, GET, "/v1/somepath", nil, nil)
+		members := DecodeProblem(t, res)
+
+		if res.Code != c.status || members["detail"] != c.detail {
+			t.Errorf("Test failed, unexpected problem %d '%v' for '%v'.", res.Code, members, c.err)
+		}
+	}
+}
+
+func TestProblemDetailsPanicResponse(t *testing.T) {
+	mux := badger.NewMux()
+	mux.ProblemDetails = true
+	mux.PanicHandler = badger.NewRecovery(func(req *http.Request, err *badger.PanicError) {}).PanicHandler
+	mux.AddRouter(RouterBasePath1).Get(RoutePath1, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		panic("oops")
+	}))
+
+	req, _ := http.NewRequest(GET, "/v1/somepath", nil)
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, req)
+
+	if members := DecodeProblem(t, res); members["status"] != float64(http.StatusInternalServerError) {
+		t.Errorf("Test failed, unexpected problem '%v'.", members)
+	}
+}
+
+func TestProblemPanicResponse(t *testing.T) {
+	recovery := badger.NewRecovery(func(req *http.Request, err *badger.PanicError) {})
+	recovery.Respond = badger.ProblemPanicResponse
+
+	handler := recovery.Middleware(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		panic("oops")
+	}))
+
+	res := ServeRequest(handler, GET, "/v1/somepath", nil, nil)
+
+	if members := DecodeProblem(t, res); members["status"] != float64(http.StatusInternalServerError) {
+		t.Errorf("Test failed, unexpected problem '%v'.", members)
+	}
+}
@@ -0,0 +1,124 @@
+package badger
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+// PanicError is a recovered panic normalised to an error
+type PanicError struct {
+	// Err is the panic value if it is an error, or an error describing it
+	Err error
+	// Value is the value the handler panicked with
+	Value interface{}
+	// Stack is the stack trace of the panicking goroutine
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %s", e.Err.Error())
+}
+
+func (e *PanicError) Unwrap() error {
+	return e.Err
+}
+
+// PanicSink receives every panic recovered by a Recovery
+type PanicSink func(*http.Request, *PanicError)
+
+// Recovery recovers panics from handlers, reports them to Sink and answers
+// the request with Respond unless the response was already started. Panics
+// with http.ErrAbortHandler are not recovered, so the server aborts the
+// response as usual.
+//
+// Use Middleware on a router, registered last so it wraps the other
+// middlewares, or PanicHandler as the Mux PanicHandler.
+type Recovery struct {
+	// Sink receives recovered panics, defaults to logging them with the
+	// standard logger
+	Sink PanicSink
+	// Respond writes the response, defaults to a 500 Internal Server Error,
+	// as a problem when the Mux has ProblemDetails enabled
+	Respond func(http.ResponseWriter, *http.Request, *PanicError)
+}
+
+// NewRecovery returns a Recovery reporting panics to sink, a nil sink logs
+// them with the standard logger
+func NewRecovery(sink PanicSink) *Recovery {
+	if sink == nil {
+		sink = LogPanicSink(log.Default())
+	}
+
+	return &Recovery{sink, nil}
+}
+
+// LogPanicSink returns a PanicSink printing panics and their stack trace to
+// logger
+func LogPanicSink(logger *log.Logger) PanicSink {
+	return func(req *http.Request, err *PanicError) {
+		logger.Printf("%s %s: %s\n%s", req.Method, req.URL.Path, err.Error(), err.Stack)
+	}
+}
+
+// Middleware recovers panics from the given handler
+func (rc *Recovery) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		rw := trackResponseWriter(res)
+
+		defer func() {
+			if p := recover(); p != nil {
+				rc.PanicHandler(rw, req, p)
+			}
+		}()
+
+		h.ServeHTTP(rw, req)
+	})
+}
+
+// PanicHandler handles a recovered panic value, it matches the Mux
+// PanicHandler signature and must be called from the deferred function
+// which recovered the panic, to capture the right stack
+func (rc *Recovery) PanicHandler(res http.ResponseWriter, req *http.Request, p interface{}) {
+	if p == http.ErrAbortHandler {
+		panic(p)
+	}
+
+	// Panics raised again from another goroutine carry their own stack
+	err, ok := p.(*PanicError)
+
+	if !ok {
+		err = &PanicError{toError(p), p, debug.Stack()}
+	}
+
+	if rc.Sink != nil {
+		rc.Sink(req, err)
+	}
+
+	if tw, ok := res.(ResponseWriter); ok && tw.WroteHeader() {
+		return
+	}
+
+	respond := rc.Respond
+
+	if respond == nil && problemDetailsEnabled(req) {
+		respond = ProblemPanicResponse
+	} else if respond == nil {
+		respond = defaultPanicResponse
+	}
+
+	respond(res, req, err)
+}
+
+func defaultPanicResponse(res http.ResponseWriter, req *http.Request, err *PanicError) {
+	http.Error(res, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+func toError(p interface{}) error {
+	if err, ok := p.(error); ok {
+		return err
+	}
+
+	return fmt.Errorf("%v", p)
+}
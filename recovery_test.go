@@ -0,0 +1,113 @@
+package badger_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+var ErrBoom = errors.New("boom")
+
+func TestRecoveryMiddleware(t *testing.T) {
+	var recovered *badger.PanicError
+	recovery := badger.NewRecovery(func(req *http.Request, err *badger.PanicError) {
+		recovered = err
+	})
+
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get(RoutePath1, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		panic(ErrBoom)
+	}))
+	router.Use(recovery.Middleware)
+
+	res := ServeRequest(mux, GET, "/v1/somepath", nil, nil)
+
+	if res.Code != http.StatusInternalServerError {
+		t.Errorf("Test failed, expected status %d got %d.", http.StatusInternalServerError, res.Code)
+	}
+
+	if recovered == nil || !errors.Is(recovered, ErrBoom) {
+		t.Fatalf("Test failed, expected sink to receive '%v' got '%v'.", ErrBoom, recovered)
+	}
+
+	if !strings.Contains(string(recovered.Stack), "recovery_test.go") {
+		t.Error("Test failed, expected stack to contain the panicking handler.")
+	}
+}
+
+func TestRecoveryNormalisesValues(t *testing.T) {
+	var recovered *badger.PanicError
+	recovery := badger.NewRecovery(func(req *http.Request, err *badger.PanicError) {
+		recovered = err
+	})
+
+	mux := badger.NewMux()
+	mux.PanicHandler = recovery.PanicHandler
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get(RoutePath1, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		panic(42)
+	}))
+
+	ServeRequest(mux, GET, "/v1/somepath", nil, nil)
+
+	if recovered == nil || recovered.Error() != "panic: 42" || recovered.Value != 42 {
+		t.Errorf("Test failed, unexpected recovered panic '%v'.", recovered)
+	}
+}
+
+func TestRecoveryDoesNotWriteStartedResponses(t *testing.T) {
+	recovery := badger.NewRecovery(func(req *http.Request, err *badger.PanicError) {})
+
+	mux := badger.NewMux()
+	mux.PanicHandler = recovery.PanicHandler
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get(RoutePath1, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusAccepted)
+		fmt.Fprint(res, "partial")
+		panic(ErrBoom)
+	}))
+
+	res := ServeRequest(mux, GET, "/v1/somepath", nil, nil)
+
+	if res.Code != http.StatusAccepted || res.Body.String() != "partial" {
+		t.Errorf("Test failed, unexpected response %d '%s'.", res.Code, res.Body.String())
+	}
+}
+
+func TestRecoveryCustomResponse(t *testing.T) {
+	recovery := badger.NewRecovery(func(req *http.Request, err *badger.PanicError) {})
+	recovery.Respond = func(res http.ResponseWriter, req *http.Request, err *badger.PanicError) {
+		res.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	handler := recovery.Middleware(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		panic("oops")
+	}))
+
+	if res := ServeRequest(handler, GET, "/v1/somepath", nil, nil); res.Code != http.StatusServiceUnavailable {
+		t.Errorf("Test failed, expected status %d got %d.", http.StatusServiceUnavailable, res.Code)
+	}
+}
+
+func TestRecoveryRepanicsErrAbortHandler(t *testing.T) {
+	recovery := badger.NewRecovery(func(req *http.Request, err *badger.PanicError) {
+		t.Error("Test failed, sink must not be called.")
+	})
+
+	handler := recovery.Middleware(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Errorf("Test failed, expected http.ErrAbortHandler panic got '%v'.", p)
+		}
+	}()
+
+	ServeRequest(handler, GET, "/v1/somepath", nil, nil)
+}
@@ -0,0 +1,233 @@
+package badger
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+)
+
+// DefaultMaxDecompressedBytes is the default size limit of decoded request
+// bodies for routes with no MaxBytes
+const DefaultMaxDecompressedBytes = 10 << 20
+
+// DefaultMaxDecompressionRatio is the default limit of the decoded to
+// encoded size ratio of request bodies
+const DefaultMaxDecompressionRatio = 100
+
+// decompression ratios are only checked past this decoded size, small
+// bodies often compress very well
+const minRatioCheckedBytes = 64 << 10
+
+// MaxBytesKey holds the request body size limit of a route, in bytes, set
+// with SetMaxBytes and WithMaxBytes
+var MaxBytesKey = NewMetaKey[int64]("max_bytes")
+
+// ErrDecompressionRatio is returned reading request bodies decoding to far
+// more than their encoded size
+var ErrDecompressionRatio = ErrRequestTooLarge.Wrap(errors.New("decompression ratio exceeded"))
+
+// SetMaxBytes limits the request body size of every route of the router,
+// routes may override it with WithMaxBytes. Larger bodies are answered with
+// 413 by the error handler, or make reads fail with http.MaxBytesError.
+func (r *Router) SetMaxBytes(n int64) {
+	r.WithMeta(MaxBytesKey, n)
+}
+
+// WithMaxBytes limits the request body size of the route and returns it, 0
+// removes the router limit
+func (r *Route) WithMaxBytes(n int64) *Route {
+	return r.WithMeta(MaxBytesKey, n)
+}
+
+// maxBytesHandler rejects bodies announcing more than n bytes and stops
+// reading the others after n bytes
+func maxBytesHandler(n int64, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.ContentLength > n {
+			handleError(res, req, &http.MaxBytesError{Limit: n})
+			return
+		}
+
+		// Shallow copy, the request of the outer handlers keeps its body
+		limited := *req
+		limited.Body = http.MaxBytesReader(res, req.Body, n)
+
+		h.ServeHTTP(res, &limited)
+	})
+}
+
+// Decompression is a middleware decoding gzip and deflate request bodies,
+// the handler reads the decoded body with no Content-Encoding. The decoded
+// size is limited to the route MaxBytes, or MaxBytes if the route has none,
+// and so is the decoded to encoded size ratio.
+type Decompression struct {
+	// MaxBytes limits the decoded size for routes with no MaxBytes,
+	// defaults to DefaultMaxDecompressedBytes
+	MaxBytes int64
+	// MaxRatio limits the decoded to encoded size ratio, defaults to
+	// DefaultMaxDecompressionRatio
+	MaxRatio int64
+}
+
+// NewDecompression returns a Decompression with the default limits
+func NewDecompression() *Decompression {
+	return &Decompression{}
+}
+
+// Middleware decodes the request bodies of the given handler, bodies with
+// an unsupported encoding are answered with 415 and invalid ones with 400
+func (d *Decompression) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		codings := parseHeaderList(req.Header.Values("Content-Encoding"))
+		codings = slices.DeleteFunc(codings, func(coding string) bool { return coding == "identity" })
+
+		if len(codings) == 0 || req.Body == nil || req.Body == http.NoBody {
+			h.ServeHTTP(res, req)
+			return
+		}
+
+		encoded := &countingReader{req.Body, 0}
+		body := io.Reader(encoded)
+		closers := []io.Closer{}
+
+		defer func() {
+			for _, closer := range closers {
+				closer.Close()
+			}
+		}()
+
+		// Codings are listed in the order they were applied
+		for i := len(codings) - 1; i >= 0; i-- {
+			var decoder io.ReadCloser
+			var err error
+
+			switch codings[i] {
+			case "gzip", "x-gzip":
+				decoder, err = gzip.NewReader(body)
+			case "deflate":
+				// HTTP deflate is the zlib format, RFC 9110 section 8.4.1.2
+				decoder, err = zlib.NewReader(body)
+			default:
+				res.Header().Set("Accept-Encoding", "gzip, deflate")
+				handleError(res, req, ErrUnsupportedMediaType)
+				return
+			}
+
+			if err != nil {
+				handleError(res, req, decodeError(err))
+				return
+			}
+
+			body = decoder
+			closers = append(closers, decoder)
+		}
+
+		// Shallow copy, the request of the outer handlers keeps its encoded
+		// body and headers
+		decoded := *req
+		decoded.Header = req.Header.Clone()
+		decoded.Header.Del("Content-Encoding")
+		decoded.Header.Del("Content-Length")
+		decoded.ContentLength = -1
+		decoded.Body = &decodedReader{body, req.Body, encoded, d.maxBytes(req), d.maxRatio(), 0}
+
+		h.ServeHTTP(res, &decoded)
+	})
+}
+
+func (d *Decompression) maxBytes(req *http.Request) int64 {
+	if n, ok := MaxBytesKey.FromContext(req.Context()); ok && n > 0 {
+		return n
+	}
+
+	if d.MaxBytes > 0 {
+		return d.MaxBytes
+	}
+
+	return DefaultMaxDecompressedBytes
+}
+
+func (d *Decompression) maxRatio() int64 {
+	if d.MaxRatio > 0 {
+		return d.MaxRatio
+	}
+
+	return DefaultMaxDecompressionRatio
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	c.n += int64(n)
+
+	return n, err
+}
+
+// decodedReader enforces the decoded size and ratio limits
+type decodedReader struct {
+	r       io.Reader
+	body    io.Closer
+	encoded *countingReader
+	limit   int64
+	ratio   int64
+	n       int64
+}
+
+func (d *decodedReader) Read(b []byte) (int, error) {
+	if d.n > d.limit {
+		return 0, &http.MaxBytesError{Limit: d.limit}
+	}
+
+	// Reading one byte past the limit tells a body of exactly limit bytes
+	// from a larger one
+	if int64(len(b)) > d.limit-d.n+1 {
+		b = b[:d.limit-d.n+1]
+	}
+
+	n, err := d.r.Read(b)
+	d.n += int64(n)
+
+	if d.n > d.limit {
+		return n - int(d.n-d.limit), &http.MaxBytesError{Limit: d.limit}
+	}
+
+	if d.n > minRatioCheckedBytes && d.n > d.ratio*d.encoded.n {
+		return n, ErrDecompressionRatio
+	}
+
+	if err != nil && err != io.EOF {
+		err = decodeError(err)
+	}
+
+	return n, err
+}
+
+func (d *decodedReader) Close() error {
+	return d.body.Close()
+}
+
+// decodeError reports corrupted bodies as client errors, keeping size limit
+// errors from the underlying body
+func decodeError(err error) error {
+	var maxbyteserror *http.MaxBytesError
+	if errors.As(err, &maxbyteserror) {
+		return err
+	}
+
+	var corrupt flate.CorruptInputError
+	if errors.As(err, &corrupt) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, gzip.ErrHeader) ||
+		errors.Is(err, gzip.ErrChecksum) || errors.Is(err, zlib.ErrHeader) || errors.Is(err, zlib.ErrChecksum) ||
+		errors.Is(err, zlib.ErrDictionary) || errors.Is(err, io.EOF) {
+		return ErrBadRequest.Wrap(err)
+	}
+
+	return err
+}
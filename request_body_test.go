@@ -0,0 +1,168 @@
+package badger_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+// EchoHandlerE writes the request body back
+func EchoHandlerE(res http.ResponseWriter, req *http.Request) error {
+	body, err := io.ReadAll(req.Body)
+
+	if err != nil {
+		return err
+	}
+
+	res.Header().Set("X-Content-Encoding", req.Header.Get("Content-Encoding"))
+	res.Write(body)
+	return nil
+}
+
+// ContentEncoding returns the headers of a body encoded with encoding
+func ContentEncoding(encoding string) map[string]string {
+	return map[string]string{"Content-Encoding": encoding}
+}
+
+func Gzip(content string) *bytes.Buffer {
+	var buf bytes.Buffer
+
+	w := gzip.NewWriter(&buf)
+	io.WriteString(w, content)
+	w.Close()
+
+	return &buf
+}
+
+// Chunked hides the body length, as chunked requests do
+type Chunked struct {
+	io.Reader
+}
+
+func TestMaxBytes(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetMaxBytes(1000)
+	router.Use(badger.NewDecompression().Middleware)
+	router.Post("echo", badger.HandlerE(EchoHandlerE))
+	router.Post("unlimited", badger.HandlerE(EchoHandlerE)).WithMaxBytes(0)
+
+	large := strings.Repeat("a", 1001)
+
+	tests := []struct {
+		url    string
+		body   io.Reader
+		status int
+	}{
+		{"/v1/echo", strings.NewReader(strings.Repeat("a", 1000)), http.StatusOK},
+		{"/v1/echo", strings.NewReader(large), http.StatusRequestEntityTooLarge},
+		{"/v1/echo", Chunked{strings.NewReader(large)}, http.StatusRequestEntityTooLarge},
+		{"/v1/unlimited", strings.NewReader(large), http.StatusOK},
+	}
+
+	for _, test := range tests {
+		if res := ServeRequest(mux, POST, test.url, test.body, nil); res.Code != test.status {
+			t.Errorf("Test failed, '%s' expected status %d got %d.", test.url, test.status, res.Code)
+		}
+	}
+}
+
+func TestDecompression(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetMaxBytes(1000)
+	router.Use(badger.NewDecompression().Middleware)
+	router.Post("echo", badger.HandlerE(EchoHandlerE))
+
+	res := ServeRequest(mux, POST, "/v1/echo", Gzip("hello gzip"), ContentEncoding("gzip"))
+
+	if res.Code != http.StatusOK || res.Body.String() != "hello gzip" || res.Header().Get("X-Content-Encoding") != "" {
+		t.Errorf("Test failed, unexpected response %d '%s'.", res.Code, res.Body.String())
+	}
+
+	var buf bytes.Buffer
+	w, _ := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+	io.WriteString(w, "hello deflate")
+	w.Close()
+
+	res = ServeRequest(mux, POST, "/v1/echo", &buf, ContentEncoding("deflate"))
+
+	if res.Code != http.StatusOK || res.Body.String() != "hello deflate" {
+		t.Errorf("Test failed, unexpected response %d '%s'.", res.Code, res.Body.String())
+	}
+
+	res = ServeRequest(mux, POST, "/v1/echo", Gzip(Gzip("twice").String()), ContentEncoding("gzip, gzip"))
+
+	if res.Code != http.StatusOK || res.Body.String() != "twice" {
+		t.Errorf("Test failed, unexpected response %d '%s'.", res.Code, res.Body.String())
+	}
+}
+
+func TestDecompressionLimits(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetMaxBytes(1000)
+	router.Use(badger.NewDecompression().Middleware)
+	router.Post("echo", badger.HandlerE(EchoHandlerE))
+	router.Post("large", badger.HandlerE(EchoHandlerE)).WithMaxBytes(50 << 20)
+
+	// Small on the wire but larger than the route limit once decoded
+	res := ServeRequest(mux, POST, "/v1/echo", Gzip(strings.Repeat("a", 2000)), ContentEncoding("gzip"))
+
+	if res.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Test failed, expected decoded limit to apply got %d.", res.Code)
+	}
+
+	// Decompression bomb within the size limit
+	res = ServeRequest(mux, POST, "/v1/large", Gzip(strings.Repeat("a", 20<<20)), ContentEncoding("gzip"))
+
+	if res.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Test failed, expected ratio limit to apply got %d.", res.Code)
+	}
+}
+
+func TestDecompressionInvalidBodies(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetMaxBytes(1000)
+	router.Use(badger.NewDecompression().Middleware)
+	router.Post("echo", badger.HandlerE(EchoHandlerE))
+
+	res := ServeRequest(mux, POST, "/v1/echo", strings.NewReader("not gzip"), ContentEncoding("gzip"))
+
+	if res.Code != http.StatusBadRequest {
+		t.Errorf("Test failed, expected status 400 got %d.", res.Code)
+	}
+
+	truncated := Gzip(strings.Repeat("abc", 100))
+	truncated.Truncate(truncated.Len() - 10)
+	res = ServeRequest(mux, POST, "/v1/echo", truncated, ContentEncoding("gzip"))
+
+	if res.Code != http.StatusBadRequest {
+		t.Errorf("Test failed, expected status 400 got %d.", res.Code)
+	}
+
+	// Raw DEFLATE streams lack the zlib header
+	var raw bytes.Buffer
+	w, _ := flate.NewWriter(&raw, flate.BestCompression)
+	io.WriteString(w, "raw deflate")
+	w.Close()
+	res = ServeRequest(mux, POST, "/v1/echo", &raw, ContentEncoding("deflate"))
+
+	if res.Code != http.StatusBadRequest {
+		t.Errorf("Test failed, expected status 400 got %d.", res.Code)
+	}
+
+	res = ServeRequest(mux, POST, "/v1/echo", strings.NewReader("brotli"), ContentEncoding("br"))
+
+	if res.Code != http.StatusUnsupportedMediaType || res.Header().Get("Accept-Encoding") != "gzip, deflate" {
+		t.Errorf("Test failed, expected status 415 got %d '%v'.", res.Code, res.Header())
+	}
+}
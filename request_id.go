@@ -0,0 +1,131 @@
+package badger
+
+import (
+	"context"
+	"net/http"
+)
+
+// DefaultRequestIDHeader is the header request ids are read from and
+// written to by default
+const DefaultRequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// RequestID is a middleware assigning an id to every request, the incoming
+// one is kept when valid, otherwise a new one is generated. The id is
+// echoed in the response and available through RequestIDFromContext.
+type RequestID struct {
+	// Header holds the request id, defaults to X-Request-ID
+	Header string
+	// Generate returns a new request id, defaults to a random UUID
+	Generate func() string
+	// Validate reports whether an incoming id can be kept, defaults to ids
+	// of up to 128 letters, digits and -_.: characters
+	Validate func(string) bool
+}
+
+// NewRequestID returns a RequestID with the default header, generator and
+// validation
+func NewRequestID() *RequestID {
+	return &RequestID{DefaultRequestIDHeader, newUUID, validRequestID}
+}
+
+// Middleware assigns the request id before calling the given handler
+func (rid *RequestID) Middleware(h http.Handler) http.Handler {
+	header := rid.Header
+
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		id := req.Header.Get(header)
+
+		if id == "" || !rid.validate(id) {
+			id = rid.generate()
+		}
+
+		res.Header().Set(header, id)
+		req = req.WithContext(context.WithValue(req.Context(), requestIDKey{}, id))
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+func (rid *RequestID) generate() string {
+	if rid.Generate != nil {
+		return rid.Generate()
+	}
+
+	return newUUID()
+}
+
+func (rid *RequestID) validate(id string) bool {
+	if rid.Validate != nil {
+		return rid.Validate(id)
+	}
+
+	return validRequestID(id)
+}
+
+// RequestIDFromContext returns the request id assigned by RequestID, or ""
+// if there is none
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestIDTransport is an http.RoundTripper forwarding the request id found
+// in the outgoing request context, so calls to other services share it
+type RequestIDTransport struct {
+	// Base sends the requests, defaults to http.DefaultTransport
+	Base http.RoundTripper
+	// Header holds the request id, defaults to X-Request-ID
+	Header string
+}
+
+// RoundTrip sends req with the request id header set
+func (t *RequestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	header := t.Header
+
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+
+	id := RequestIDFromContext(req.Context())
+
+	if id == "" || req.Header.Get(header) != "" {
+		return base.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the given request
+	req = req.Clone(req.Context())
+	req.Header.Set(header, id)
+
+	return base.RoundTrip(req)
+}
+
+func validRequestID(id string) bool {
+	if len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		valid := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == ':'
+
+		if !valid {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,101 @@
+package badger_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func ServeRequestID(rid *badger.RequestID, header string, incoming string) (*httptest.ResponseRecorder, string) {
+	seen := ""
+	handler := rid.Middleware(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		seen = badger.RequestIDFromContext(req.Context())
+	}))
+
+	res := ServeRequest(handler, GET, "/v1/somepath", nil, map[string]string{header: incoming})
+
+	return res, seen
+}
+
+func TestRequestIDGenerated(t *testing.T) {
+	res, seen := ServeRequestID(badger.NewRequestID(), badger.DefaultRequestIDHeader, "")
+
+	if !uuidRegexp.MatchString(seen) {
+		t.Errorf("Test failed, expected a UUID got '%s'.", seen)
+	}
+
+	AssertHeader(t, res, badger.DefaultRequestIDHeader, seen)
+}
+
+func TestRequestIDKeepsValidIncomingID(t *testing.T) {
+	res, seen := ServeRequestID(badger.NewRequestID(), badger.DefaultRequestIDHeader, "abc-123")
+
+	if seen != "abc-123" {
+		t.Errorf("Test failed, expected 'abc-123' got '%s'.", seen)
+	}
+
+	AssertHeader(t, res, badger.DefaultRequestIDHeader, "abc-123")
+}
+
+func TestRequestIDReplacesInvalidIncomingID(t *testing.T) {
+	for _, incoming := range []string{"<script>", strings.Repeat("a", 129)} {
+		if _, seen := ServeRequestID(badger.NewRequestID(), badger.DefaultRequestIDHeader, incoming); seen == incoming {
+			t.Errorf("Test failed, expected '%s' to be replaced.", incoming)
+		}
+	}
+}
+
+func TestRequestIDCustomHeaderAndGenerator(t *testing.T) {
+	rid := &badger.RequestID{Header: "X-Correlation-ID", Generate: func() string { return "generated" }}
+
+	res, seen := ServeRequestID(rid, "X-Correlation-ID", "")
+
+	if seen != "generated" {
+		t.Errorf("Test failed, expected 'generated' got '%s'.", seen)
+	}
+
+	AssertHeader(t, res, "X-Correlation-ID", "generated")
+}
+
+func TestRequestIDFromContextEmpty(t *testing.T) {
+	req, _ := http.NewRequest(GET, "/v1/somepath", nil)
+
+	if id := badger.RequestIDFromContext(req.Context()); id != "" {
+		t.Errorf("Test failed, expected empty id got '%s'.", id)
+	}
+}
+
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRequestIDTransport(t *testing.T) {
+	forwarded := ""
+	transport := &badger.RequestIDTransport{Base: RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		forwarded = req.Header.Get(badger.DefaultRequestIDHeader)
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})}
+
+	handler := badger.NewRequestID().Middleware(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		outgoing, _ := http.NewRequestWithContext(req.Context(), GET, "http://other/service", nil)
+		transport.RoundTrip(outgoing)
+
+		if outgoing.Header.Get(badger.DefaultRequestIDHeader) != "" {
+			t.Error("Test failed, the outgoing request must not be modified.")
+		}
+	}))
+
+	ServeRequest(handler, GET, "/v1/somepath", nil, map[string]string{badger.DefaultRequestIDHeader: "abc-123"})
+
+	if forwarded != "abc-123" {
+		t.Errorf("Test failed, expected forwarded id 'abc-123' got '%s'.", forwarded)
+	}
+}
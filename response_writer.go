@@ -0,0 +1,267 @@
+package badger
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+)
+
+// ResponseWriter is an http.ResponseWriter recording the response status
+// and size, created by WrapResponseWriter
+type ResponseWriter interface {
+	http.ResponseWriter
+
+	// Status returns the status sent, 0 until the header is written
+	Status() int
+
+	// Written returns the number of body bytes written
+	Written() int64
+
+	// WroteHeader reports whether the header was written, after which the
+	// status and headers can no longer change
+	WroteHeader() bool
+
+	// Unwrap returns the wrapped writer, for http.ResponseController
+	Unwrap() http.ResponseWriter
+}
+
+// ResponseWriterHooks are called by a ResponseWriter as the response is
+// written
+type ResponseWriterHooks struct {
+	// BeforeWriteHeader is called once right before the header is written,
+	// explicitly or by the first write, headers can still be changed
+	BeforeWriteHeader func(status int)
+
+	// OnWrite is called after every body write, b is nil for writes done
+	// through io.ReaderFrom
+	OnWrite func(b []byte, n int64, err error)
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	hooks       ResponseWriterHooks
+	status      int
+	written     int64
+	wroteheader bool
+}
+
+// WrapResponseWriter returns a ResponseWriter calling hooks, it implements
+// http.Flusher, http.Hijacker, io.ReaderFrom and http.Pusher only when w
+// does, so wrapping does not break streaming, websockets or sendfile
+func WrapResponseWriter(w http.ResponseWriter, hooks ResponseWriterHooks) ResponseWriter {
+	rw := &responseWriter{w, hooks, 0, 0, false}
+
+	_, isflusher := w.(http.Flusher)
+	_, ishijacker := w.(http.Hijacker)
+	_, isreaderfrom := w.(io.ReaderFrom)
+	_, ispusher := w.(http.Pusher)
+
+	f, h, r, p := flusher{rw}, hijacker{rw}, readerFrom{rw}, pusher{rw}
+
+	switch {
+	case isflusher && ishijacker && isreaderfrom && ispusher:
+		return struct {
+			*responseWriter
+			http.Flusher
+			http.Hijacker
+			io.ReaderFrom
+			http.Pusher
+		}{rw, f, h, r, p}
+	case isflusher && ishijacker && isreaderfrom:
+		return struct {
+			*responseWriter
+			http.Flusher
+			http.Hijacker
+			io.ReaderFrom
+		}{rw, f, h, r}
+	case isflusher && ishijacker && ispusher:
+		return struct {
+			*responseWriter
+			http.Flusher
+			http.Hijacker
+			http.Pusher
+		}{rw, f, h, p}
+	case isflusher && isreaderfrom && ispusher:
+		return struct {
+			*responseWriter
+			http.Flusher
+			io.ReaderFrom
+			http.Pusher
+		}{rw, f, r, p}
+	case ishijacker && isreaderfrom && ispusher:
+		return struct {
+			*responseWriter
+			http.Hijacker
+			io.ReaderFrom
+			http.Pusher
+		}{rw, h, r, p}
+	case isflusher && ishijacker:
+		return struct {
+			*responseWriter
+			http.Flusher
+			http.Hijacker
+		}{rw, f, h}
+	case isflusher && isreaderfrom:
+		return struct {
+			*responseWriter
+			http.Flusher
+			io.ReaderFrom
+		}{rw, f, r}
+	case isflusher && ispusher:
+		return struct {
+			*responseWriter
+			http.Flusher
+			http.Pusher
+		}{rw, f, p}
+	case ishijacker && isreaderfrom:
+		return struct {
+			*responseWriter
+			http.Hijacker
+			io.ReaderFrom
+		}{rw, h, r}
+	case ishijacker && ispusher:
+		return struct {
+			*responseWriter
+			http.Hijacker
+			http.Pusher
+		}{rw, h, p}
+	case isreaderfrom && ispusher:
+		return struct {
+			*responseWriter
+			io.ReaderFrom
+			http.Pusher
+		}{rw, r, p}
+	case isflusher:
+		return struct {
+			*responseWriter
+			http.Flusher
+		}{rw, f}
+	case ishijacker:
+		return struct {
+			*responseWriter
+			http.Hijacker
+		}{rw, h}
+	case isreaderfrom:
+		return struct {
+			*responseWriter
+			io.ReaderFrom
+		}{rw, r}
+	case ispusher:
+		return struct {
+			*responseWriter
+			http.Pusher
+		}{rw, p}
+	}
+
+	return rw
+}
+
+func (w *responseWriter) Status() int {
+	return w.status
+}
+
+func (w *responseWriter) Written() int64 {
+	return w.written
+}
+
+func (w *responseWriter) WroteHeader() bool {
+	return w.wroteheader
+}
+
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	// Informational responses do not start the final response
+	if status < http.StatusOK {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	if w.wroteheader {
+		return
+	}
+
+	if w.hooks.BeforeWriteHeader != nil {
+		w.hooks.BeforeWriteHeader(status)
+	}
+
+	w.wroteheader = true
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+
+	n, err := w.ResponseWriter.Write(b)
+	w.written += int64(n)
+
+	if w.hooks.OnWrite != nil {
+		w.hooks.OnWrite(b, int64(n), err)
+	}
+
+	return n, err
+}
+
+type flusher struct {
+	w *responseWriter
+}
+
+func (f flusher) Flush() {
+	f.w.WriteHeader(http.StatusOK)
+	f.w.ResponseWriter.(http.Flusher).Flush()
+}
+
+type hijacker struct {
+	w *responseWriter
+}
+
+func (h hijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, buf, err := h.w.ResponseWriter.(http.Hijacker).Hijack()
+
+	if err == nil {
+		// The connection now belongs to the caller
+		h.w.wroteheader = true
+		h.w.status = http.StatusSwitchingProtocols
+	}
+
+	return conn, buf, err
+}
+
+type readerFrom struct {
+	w *responseWriter
+}
+
+func (r readerFrom) ReadFrom(src io.Reader) (int64, error) {
+	r.w.WriteHeader(http.StatusOK)
+
+	n, err := r.w.ResponseWriter.(io.ReaderFrom).ReadFrom(src)
+	r.w.written += n
+
+	if r.w.hooks.OnWrite != nil {
+		r.w.hooks.OnWrite(nil, n, err)
+	}
+
+	return n, err
+}
+
+type pusher struct {
+	w *responseWriter
+}
+
+func (p pusher) Push(target string, opts *http.PushOptions) error {
+	return p.w.ResponseWriter.(http.Pusher).Push(target, opts)
+}
+
+// trackResponseWriter returns res if it already is a ResponseWriter, or res
+// wrapped with no hooks
+func trackResponseWriter(res http.ResponseWriter) ResponseWriter {
+	if rw, ok := res.(ResponseWriter); ok {
+		return rw
+	}
+
+	return WrapResponseWriter(res, ResponseWriterHooks{})
+}
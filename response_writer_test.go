@@ -0,0 +1,126 @@
+package badger_test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+type FullResponseWriter struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+	pushed   string
+	readfrom bool
+}
+
+func (w *FullResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.hijacked = true
+	return nil, nil, nil
+}
+
+func (w *FullResponseWriter) Push(target string, opts *http.PushOptions) error {
+	w.pushed = target
+	return nil
+}
+
+func (w *FullResponseWriter) ReadFrom(r io.Reader) (int64, error) {
+	w.readfrom = true
+	return io.Copy(w.ResponseRecorder, r)
+}
+
+func TestWrapResponseWriterKeepsOnlySupportedInterfaces(t *testing.T) {
+	rw := badger.WrapResponseWriter(httptest.NewRecorder(), badger.ResponseWriterHooks{})
+
+	if _, ok := rw.(http.Flusher); !ok {
+		t.Error("Test failed, expected wrapper to be an http.Flusher.")
+	}
+
+	if _, ok := rw.(http.Hijacker); ok {
+		t.Error("Test failed, expected wrapper not to be an http.Hijacker.")
+	}
+
+	if _, ok := rw.(http.Pusher); ok {
+		t.Error("Test failed, expected wrapper not to be an http.Pusher.")
+	}
+
+	if _, ok := rw.(io.ReaderFrom); ok {
+		t.Error("Test failed, expected wrapper not to be an io.ReaderFrom.")
+	}
+}
+
+func TestWrapResponseWriterForwardsOptionalInterfaces(t *testing.T) {
+	w := &FullResponseWriter{ResponseRecorder: httptest.NewRecorder()}
+	rw := badger.WrapResponseWriter(w, badger.ResponseWriterHooks{})
+
+	rw.(http.Pusher).Push("/style.css", nil)
+	n, _ := rw.(io.ReaderFrom).ReadFrom(strings.NewReader("streamed"))
+	rw.(http.Flusher).Flush()
+	rw.(http.Hijacker).Hijack()
+
+	if w.pushed != "/style.css" || !w.readfrom || !w.hijacked || !w.Flushed {
+		t.Errorf("Test failed, optional interfaces not forwarded '%+v'.", w)
+	}
+
+	if n != 8 || rw.Written() != 8 || w.Body.String() != "streamed" {
+		t.Errorf("Test failed, expected 8 bytes written got %d.", rw.Written())
+	}
+}
+
+func TestWrapResponseWriterRecordsStatusAndSize(t *testing.T) {
+	rw := badger.WrapResponseWriter(httptest.NewRecorder(), badger.ResponseWriterHooks{})
+
+	if rw.WroteHeader() || rw.Status() != 0 {
+		t.Error("Test failed, expected header not to be written.")
+	}
+
+	rw.WriteHeader(http.StatusNotFound)
+	rw.WriteHeader(http.StatusOK)
+	rw.Write([]byte("nope"))
+
+	if !rw.WroteHeader() || rw.Status() != http.StatusNotFound || rw.Written() != 4 {
+		t.Errorf("Test failed, unexpected status %d and size %d.", rw.Status(), rw.Written())
+	}
+}
+
+func TestWrapResponseWriterHooks(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	calls := []string{}
+
+	rw := badger.WrapResponseWriter(recorder, badger.ResponseWriterHooks{
+		BeforeWriteHeader: func(status int) {
+			recorder.Header().Set(RouteHeaderKey1, HeadersExpectedValue)
+			calls = append(calls, "header")
+		},
+		OnWrite: func(b []byte, n int64, err error) {
+			calls = append(calls, string(b))
+		},
+	})
+
+	rw.Write([]byte("a"))
+	rw.Write([]byte("b"))
+
+	if strings.Join(calls, ",") != "header,a,b" {
+		t.Errorf("Test failed, unexpected hook calls '%v'.", calls)
+	}
+
+	AssertHeader(t, recorder, RouteHeaderKey1, HeadersExpectedValue)
+}
+
+func TestWrapResponseWriterUnwrap(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	rw := badger.WrapResponseWriter(recorder, badger.ResponseWriterHooks{})
+
+	if rw.Unwrap() != recorder {
+		t.Error("Test failed, expected Unwrap to return the wrapped writer.")
+	}
+
+	if err := http.NewResponseController(rw).Flush(); err != nil || !recorder.Flushed {
+		t.Errorf("Test failed, expected ResponseController to flush got '%v'.", err)
+	}
+}
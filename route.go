@@ -2,11 +2,120 @@ package badger
 
 import (
 	"net/http"
+	"slices"
 )
 
-// Route struct defines the information needed to build a route
+// Route struct defines the information needed to build a route, a single
+// route may be served for several methods
 type Route struct {
+	methods  []string
+	path     string
+	pattern  string
+	basepath string
+	name     string
+	metadata map[interface{}]interface{}
+	tags     []string
+	router   *Router
+	handler  http.Handler
+}
+
+// builtRoute is a route for a single method with the router middlewares
+// already applied to its handler
+type builtRoute struct {
 	method  string
 	path    string
 	handler http.Handler
+	route   *Route
+}
+
+func newRoute(router *Router, methods []string, path string, handler http.Handler) *Route {
+	pattern := buildRoutePath(router.basepath, path)
+	basepath := normalizeRoutePath(router.basepath)
+
+	return &Route{methods, path, pattern, basepath, "", map[interface{}]interface{}{}, []string{}, router, handler}
+}
+
+// Methods returns the methods the route is served for
+func (r *Route) Methods() []string {
+	return append([]string{}, r.methods...)
+}
+
+// Path returns the path the route was registered with
+func (r *Route) Path() string {
+	return r.path
+}
+
+// Pattern returns the full path matched by the route, including the router
+// base path
+func (r *Route) Pattern() string {
+	return r.pattern
+}
+
+// BasePath returns the base path of the router the route belongs to
+func (r *Route) BasePath() string {
+	return r.basepath
+}
+
+// Name returns the name given to the route with Named
+func (r *Route) Name() string {
+	return r.name
+}
+
+// Named names the route and returns it
+func (r *Route) Named(name string) *Route {
+	r.name = name
+	return r
+}
+
+// WithMeta sets a metadata value on the route and returns it, keys follow
+// the same rules as context keys. It overrides the router value for key.
+func (r *Route) WithMeta(key interface{}, value interface{}) *Route {
+	r.metadata[key] = value
+	return r
+}
+
+// Meta returns the metadata value for key, set on the route or inherited
+// from its router
+func (r *Route) Meta(key interface{}) (interface{}, bool) {
+	value, ok := r.Metadata()[key]
+	return value, ok
+}
+
+// Metadata returns the route metadata, including values inherited from its
+// router
+func (r *Route) Metadata() map[interface{}]interface{} {
+	metadata := map[interface{}]interface{}{}
+
+	r.router.lock.RLock()
+	for key, value := range r.router.metadata {
+		metadata[key] = value
+	}
+	r.router.lock.RUnlock()
+
+	for key, value := range r.metadata {
+		metadata[key] = value
+	}
+
+	return metadata
+}
+
+// Tagged adds tags to the route and returns it
+func (r *Route) Tagged(tags ...string) *Route {
+	r.tags = append(r.tags, tags...)
+	return r
+}
+
+// Tags returns the route tags, including the ones of its router
+func (r *Route) Tags() []string {
+	r.router.lock.RLock()
+	tags := append([]string{}, r.router.tags...)
+	r.router.lock.RUnlock()
+
+	for _, tag := range r.tags {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
 }
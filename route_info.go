@@ -0,0 +1,47 @@
+package badger
+
+import (
+	"context"
+	"slices"
+)
+
+type routeInfoKey struct{}
+
+// RouteInfo describes the route matched by a request, it is available to
+// middlewares and handlers through RouteFromContext
+type RouteInfo struct {
+	// Method is the method the route matched
+	Method string
+	// Pattern is the full path pattern of the route, such as /v1/users/:id/
+	Pattern string
+	// BasePath is the base path of the router the route belongs to
+	BasePath string
+	// Name is the name given to the route, if any
+	Name string
+	// Metadata are the values set on the route and its router, it must not
+	// be modified
+	Metadata map[interface{}]interface{}
+	// Tags are the tags of the route and its router
+	Tags []string
+}
+
+// RouteFromContext returns the route matched by the request, ok is false
+// if no route matched
+func RouteFromContext(ctx context.Context) (RouteInfo, bool) {
+	info, ok := ctx.Value(routeInfoKey{}).(*RouteInfo)
+
+	if !ok {
+		return RouteInfo{}, false
+	}
+
+	return *info, true
+}
+
+// HasTag reports whether the route is tagged with tag
+func (info RouteInfo) HasTag(tag string) bool {
+	return slices.Contains(info.Tags, tag)
+}
+
+func newRouteInfo(method string, route *Route) *RouteInfo {
+	return &RouteInfo{method, route.pattern, route.basepath, route.name, route.Metadata(), route.Tags()}
+}
@@ -0,0 +1,60 @@
+package badger_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+type RouteInfoTestKey struct{}
+
+func TestRouteFromContext(t *testing.T) {
+	var info badger.RouteInfo
+	var found bool
+
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Match([]string{GET, POST}, "users/:id", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {})).
+		Named("user").
+		WithMeta(RouteInfoTestKey{}, "value")
+
+	router.Use(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			info, found = badger.RouteFromContext(req.Context())
+			h.ServeHTTP(res, req)
+		})
+	})
+
+	req, _ := http.NewRequest(POST, "/v1/users/42", nil)
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !found {
+		t.Fatal("Test failed, expected route info to be found.")
+	}
+
+	if info.Method != POST || info.Pattern != "/v1/users/:id/" || info.BasePath != "/v1/" || info.Name != "user" {
+		t.Errorf("Test failed, unexpected route info '%+v'.", info)
+	}
+
+	if info.Metadata[RouteInfoTestKey{}] != "value" {
+		t.Errorf("Test failed, expected metadata 'value' got '%v'.", info.Metadata[RouteInfoTestKey{}])
+	}
+}
+
+func TestRouteFromContextNotMatched(t *testing.T) {
+	found := true
+
+	mux := badger.NewMux()
+	mux.NotFound = func(res http.ResponseWriter, req *http.Request) {
+		_, found = badger.RouteFromContext(req.Context())
+	}
+
+	req, _ := http.NewRequest(GET, "/nowhere", nil)
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+
+	if found {
+		t.Error("Test failed, expected no route info.")
+	}
+}
@@ -0,0 +1,44 @@
+package badger
+
+import (
+	"context"
+)
+
+// MetaKey is a typed metadata key, it reads values set with WithMeta on
+// routes and routers without type assertions
+type MetaKey[T any] struct {
+	name string
+}
+
+// NewMetaKey returns a new metadata key holding values of type T, the name
+// is only used for debugging
+func NewMetaKey[T any](name string) *MetaKey[T] {
+	return &MetaKey[T]{name}
+}
+
+func (k *MetaKey[T]) String() string {
+	return "badger meta key " + k.name
+}
+
+// Get returns the value of the key in the route info, ok is false if the
+// value is missing or not a T
+func (k *MetaKey[T]) Get(info RouteInfo) (T, bool) {
+	value, ok := info.Metadata[k].(T)
+	return value, ok
+}
+
+// FromContext returns the value of the key for the route matched by the
+// request
+func (k *MetaKey[T]) FromContext(ctx context.Context) (T, bool) {
+	info, _ := RouteFromContext(ctx)
+	return k.Get(info)
+}
+
+// FromRoute returns the value of the key set on the route or inherited from
+// its router
+func (k *MetaKey[T]) FromRoute(route *Route) (T, bool) {
+	value, _ := route.Meta(k)
+	typed, ok := value.(T)
+
+	return typed, ok
+}
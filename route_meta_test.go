@@ -0,0 +1,77 @@
+package badger_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+var roleKey = badger.NewMetaKey[string]("role")
+
+func TestRouteInheritsRouterMeta(t *testing.T) {
+	router := badger.NewRouter(RouterBasePath1).WithMeta(roleKey, "user").WithMeta("public", false)
+	route := router.Get(RoutePath1, nil).WithMeta("public", true)
+
+	if role, ok := roleKey.FromRoute(route); !ok || role != "user" {
+		t.Errorf("Test failed, expected inherited role 'user' got '%s'.", role)
+	}
+
+	if value, _ := route.Meta("public"); value != true {
+		t.Errorf("Test failed, expected route value to override router value got '%v'.", value)
+	}
+}
+
+func TestMetaKeyWrongType(t *testing.T) {
+	route := badger.NewRouter(RouterBasePath1).Get(RoutePath1, nil).WithMeta(roleKey, 10)
+
+	if _, ok := roleKey.FromRoute(route); ok {
+		t.Error("Test failed, expected value of the wrong type not to be found.")
+	}
+}
+
+func TestRouteTags(t *testing.T) {
+	router := badger.NewRouter(RouterBasePath1).Tagged("api", "v1")
+	route := router.Get(RoutePath1, nil).Tagged("users", "api")
+
+	if tags := route.Tags(); !slices.Equal(tags, []string{"api", "v1", "users"}) {
+		t.Errorf("Test failed, unexpected tags '%v'.", tags)
+	}
+}
+
+func TestMetaFromContext(t *testing.T) {
+	var role string
+	var tagged bool
+
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1).WithMeta(roleKey, "admin").Tagged("internal")
+
+	router.Use(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			role, _ = roleKey.FromContext(req.Context())
+			info, _ := badger.RouteFromContext(req.Context())
+			tagged = info.HasTag("internal")
+
+			h.ServeHTTP(res, req)
+		})
+	})
+
+	router.Get(RoutePath1, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {}))
+
+	req, _ := http.NewRequest(http.MethodGet, "/v1/somepath", nil)
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+
+	if role != "admin" || !tagged {
+		t.Errorf("Test failed, expected role 'admin' and tag got '%s' '%t'.", role, tagged)
+	}
+}
+
+func TestMetaFromContextWithoutRoute(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+
+	if _, ok := roleKey.FromContext(req.Context()); ok {
+		t.Error("Test failed, expected no value outside a route.")
+	}
+}
@@ -2,8 +2,6 @@ package badger
 
 import (
 	"net/http"
-
-	"github.com/julienschmidt/httprouter"
 )
 
 type routeParamsKey struct{}
@@ -17,8 +15,7 @@ type RouteParams struct {
 	params TypedParams
 }
 
-// CreateRouteParams converts httprouter.Params to TypedParams
-func CreateRouteParams(rps httprouter.Params) TypedParams {
+func createRouteParams(rps []Param) TypedParams {
 	dict := make(map[string]string)
 
 	for _, rp := range rps {
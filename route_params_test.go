@@ -6,68 +6,8 @@ import (
 	"testing"
 
 	"github.com/hugoluchessi/badger"
-	"github.com/julienschmidt/httprouter"
 )
 
-func TestCreateRouteParams(t *testing.T) {
-	key := "map"
-	value := "mapvalue!"
-
-	params := httprouter.Params{}
-	params = append(params, httprouter.Param{Key: key, Value: value})
-
-	typedmap := badger.CreateRouteParams(params)
-
-	rvalue, err := typedmap.GetString(key)
-
-	if err != nil {
-		t.Error("Test failed, err must be nil.")
-	}
-
-	if rvalue == "" {
-		t.Errorf("Test failed, expected value to be '%s' got '%s'.", value, rvalue)
-	}
-}
-
-func TestCreateRouteParamsWithNoParams(t *testing.T) {
-	key := "map"
-
-	params := httprouter.Params{}
-	params = append(params, httprouter.Param{})
-
-	typedmap := badger.CreateRouteParams(params)
-
-	rvalue, err := typedmap.GetString(key)
-
-	if err == nil {
-		t.Error("Test failed, err must not be nil.")
-	}
-
-	if rvalue != "" {
-		t.Errorf("Test failed, expected value to be '%s' got '%s'.", "", rvalue)
-	}
-}
-
-func TestCreateRouteIntParams(t *testing.T) {
-	key := "map"
-	value := "1234"
-
-	params := httprouter.Params{}
-	params = append(params, httprouter.Param{Key: key, Value: value})
-
-	typedmap := badger.CreateRouteParams(params)
-
-	rvalue, err := typedmap.GetInt(key)
-
-	if err != nil {
-		t.Error("Test failed, err must be nil.")
-	}
-
-	if rvalue == 0 {
-		t.Errorf("Test failed, expected value to be '%s' got '%d'.", value, rvalue)
-	}
-}
-
 func TestGetRouteParamsFromRequest(t *testing.T) {
 	key := "name"
 	value := "cool"
@@ -0,0 +1,28 @@
+package badger_test
+
+import (
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+func TestRouteAccessors(t *testing.T) {
+	router := badger.NewRouter(RouterBasePath1)
+	route := router.Get(RoutePath1, nil).Named("some")
+
+	if route.Name() != "some" || route.Path() != RoutePath1 || route.Pattern() != "/v1/somepath/" || route.BasePath() != "/v1/" {
+		t.Errorf("Test failed, unexpected route '%s' '%s' '%s' '%s'.", route.Name(), route.Path(), route.Pattern(), route.BasePath())
+	}
+}
+
+func TestRouteMeta(t *testing.T) {
+	route := badger.NewRouter(RouterBasePath1).Get(RoutePath1, nil).WithMeta("public", true)
+
+	if value, ok := route.Meta("public"); !ok || value != true {
+		t.Errorf("Test failed, expected metadata 'true' got '%v'.", value)
+	}
+
+	if _, ok := route.Meta("missing"); ok {
+		t.Error("Test failed, expected missing metadata not to be found.")
+	}
+}
@@ -1,68 +1,137 @@
 package badger
 
 import (
+	"fmt"
 	"net/http"
 	"sync"
 )
 
 type middleware func(http.Handler) http.Handler
 
+// MethodAny registers a route matching requests of any method, including
+// custom ones, for which the path has no route of their own
+const MethodAny = "*"
+
+// standardMethods are the methods registered by Any
+var standardMethods = []string{
+	http.MethodConnect,
+	http.MethodDelete,
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodPatch,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodTrace,
+}
+
 // Router is responsible for gathering all routing information and to build all
 // handler chaining
 type Router struct {
-	basepath    string
-	middlewares []middleware
-	routes      []Route
-	lock        sync.RWMutex
+	basepath         string
+	middlewares      []middleware
+	routes           []*Route
+	optionshandler   http.Handler
+	notfound         http.Handler
+	methodnotallowed http.Handler
+	errorhandler     ErrorHandler
+	metadata         map[interface{}]interface{}
+	tags             []string
+	lock             sync.RWMutex
 }
 
 // NewRouter returns a pointer to a newly created router
 func NewRouter(path string) *Router {
-	return &Router{path, []middleware{}, []Route{}, sync.RWMutex{}}
+	return &Router{path, []middleware{}, []*Route{}, DefaultOptionsHandler, nil, nil, nil, map[interface{}]interface{}{}, []string{}, sync.RWMutex{}}
 }
 
 // Delete creates a new handler for DELETE method in the router
-func (r *Router) Delete(path string, handler http.Handler) {
-	r.Handle("DELETE", path, handler)
+func (r *Router) Delete(path string, handler http.Handler) *Route {
+	return r.Handle("DELETE", path, handler)
 }
 
 // Get creates a new handler for GET method in the router
-func (r *Router) Get(path string, handler http.Handler) {
-	r.Handle("GET", path, handler)
+func (r *Router) Get(path string, handler http.Handler) *Route {
+	return r.Handle("GET", path, handler)
 }
 
 // Head creates a new handler for HEAD method in the router
-func (r *Router) Head(path string, handler http.Handler) {
-	r.Handle("HEAD", path, handler)
+func (r *Router) Head(path string, handler http.Handler) *Route {
+	return r.Handle("HEAD", path, handler)
 }
 
 // Options creates a new handler for OPTIONS method in the router
-func (r *Router) Options(path string, handler http.Handler) {
-	r.Handle("OPTIONS", path, handler)
+func (r *Router) Options(path string, handler http.Handler) *Route {
+	return r.Handle("OPTIONS", path, handler)
 }
 
 // Patch creates a new handler for PATCH method in the router
-func (r *Router) Patch(path string, handler http.Handler) {
-	r.Handle("PATCH", path, handler)
+func (r *Router) Patch(path string, handler http.Handler) *Route {
+	return r.Handle("PATCH", path, handler)
 }
 
 // Post creates a new handler for POST method in the router
-func (r *Router) Post(path string, handler http.Handler) {
-	r.Handle("POST", path, handler)
+func (r *Router) Post(path string, handler http.Handler) *Route {
+	return r.Handle("POST", path, handler)
 }
 
 // Put creates a new handler for PUT method in the router
-func (r *Router) Put(path string, handler http.Handler) {
-	r.Handle("PUT", path, handler)
+func (r *Router) Put(path string, handler http.Handler) *Route {
+	return r.Handle("PUT", path, handler)
 }
 
 // Handle creates a new handler for the given method in the router
-func (r *Router) Handle(method string, path string, handler http.Handler) {
+func (r *Router) Handle(method string, path string, handler http.Handler) *Route {
+	return r.Match([]string{method}, path, handler)
+}
+
+// Any creates a new handler for every standard method in the router
+func (r *Router) Any(path string, handler http.Handler) *Route {
+	return r.Match(standardMethods, path, handler)
+}
+
+// Match creates a new handler for each of the given methods in the router,
+// custom methods such as WebDAV ones are allowed. It is reported as a
+// single route.
+func (r *Router) Match(methods []string, path string, handler http.Handler) *Route {
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
-	route := Route{method, path, handler}
+	if len(methods) == 0 {
+		panic(fmt.Sprintf("no methods given for path '%s'", path))
+	}
+
+	route := newRoute(r, append([]string{}, methods...), path, handler)
 	r.routes = append(r.routes, route)
+
+	return route
+}
+
+// Routes returns the routes registered in the router
+func (r *Router) Routes() []*Route {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return append([]*Route{}, r.routes...)
+}
+
+// WithMeta sets a metadata value inherited by every route of the router and
+// returns it, keys follow the same rules as context keys
+func (r *Router) WithMeta(key interface{}, value interface{}) *Router {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.metadata[key] = value
+	return r
+}
+
+// Tagged adds tags inherited by every route of the router and returns it
+func (r *Router) Tagged(tags ...string) *Router {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.tags = append(r.tags, tags...)
+	return r
 }
 
 // Use creates a new middleware for the given functions
@@ -1,6 +1,7 @@
 package badger_test
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -23,6 +24,21 @@ func AssertHandlerFunc(ehandlerheaderkey string, ehandlerheadervalue string) fun
 	}
 }
 
+// ServeRequest serves a request with the given body and headers through
+// handler and returns the recorded response
+func ServeRequest(handler http.Handler, method string, url string, body io.Reader, headers map[string]string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, url, body)
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	return res
+}
+
 func AssertRoute(t *testing.T, mux *badger.Mux, emethod string, epath string, ehandlerheaderkey string, ehandlerheadervalue string) {
 	req, _ := http.NewRequest(emethod, epath, nil)
 	res := httptest.NewRecorder()
@@ -312,3 +328,57 @@ func TestUseMultiple(t *testing.T) {
 		t.Errorf("Test failed, invalid 'X-Middleware' header value, got '%s' expected '%s'.", mw2header2, "YEAH!")
 	}
 }
+
+func TestAny(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Any(RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+	for _, method := range []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"} {
+		AssertRoute(t, mux, method, "/v1/somepath", RouteHeaderKey1, HeadersExpectedValue)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Match([]string{"GET", "PROPFIND"}, RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+	AssertRoute(t, mux, "GET", "/v1/somepath", RouteHeaderKey1, HeadersExpectedValue)
+	AssertRoute(t, mux, "PROPFIND", "/v1/somepath", RouteHeaderKey1, HeadersExpectedValue)
+	AssertRoute(t, mux, "POST", "/v1/somepath", RouteHeaderKey1, "")
+}
+
+func TestMatchWithoutMethodsPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Test failed, expected a panic.")
+		}
+	}()
+
+	badger.NewRouter("").Match([]string{}, "somepath", nil)
+}
+
+func TestMatchIsOneRoute(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Match([]string{"GET", "POST"}, RoutePath1, http.HandlerFunc(AssertHandlerFunc(RouteHeaderKey1, HeadersExpectedValue)))
+
+	routes := mux.Routes()
+
+	if len(routes) != 1 {
+		t.Fatalf("Test failed, expected 1 route got %d.", len(routes))
+	}
+
+	if methods := routes[0].Methods(); len(methods) != 2 || methods[0] != "GET" || methods[1] != "POST" {
+		t.Errorf("Test failed, unexpected methods '%v'.", methods)
+	}
+
+	if routes[0].Path() != RoutePath1 {
+		t.Errorf("Test failed, expected path '%s' got '%s'.", RoutePath1, routes[0].Path())
+	}
+
+	if routes[0].Pattern() != "/v1/somepath/" {
+		t.Errorf("Test failed, expected pattern '/v1/somepath/' got '%s'.", routes[0].Pattern())
+	}
+}
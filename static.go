@@ -0,0 +1,254 @@
+package badger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const staticParamKey = "badgerstaticpath"
+
+const defaultIndexFile = "index.html"
+
+const defaultHashedMaxAge = 365 * 24 * time.Hour
+
+// hashedFileRegexp matches file names ending with what may be a content
+// hash such as app.3f9a2c1d.js or chunk-5d41402abc4b2a76.css
+var hashedFileRegexp = regexp.MustCompile(`[.\-_]([0-9a-fA-F]{8,})\.[^.]+$`)
+
+// HashedFileName reports whether the file name carries a content hash of
+// at least 8 hex characters, such as app.3f9a2c1d.js. Decimal only runs such
+// as report-20240101.pdf are dates or ids, not hashes.
+func HashedFileName(name string) bool {
+	match := hashedFileRegexp.FindStringSubmatch(path.Base(name))
+	return match != nil && strings.ContainsAny(match[1], "abcdefABCDEF")
+}
+
+// precompressedEncodings are the encodings looked for as siblings of a
+// file, in order of preference
+var precompressedEncodings = []struct {
+	encoding  string
+	extension string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// StaticOptions configures how files are served by Router.Static
+type StaticOptions struct {
+	// IndexFile is served for directory paths, defaults to index.html
+	IndexFile string
+
+	// Precompressed serves the .br or .gz sibling of a file when it exists
+	// and the client accepts that encoding
+	Precompressed bool
+
+	// Hashed reports whether the named file carries a content hash, such
+	// files never change and are cached as immutable for HashedMaxAge. No
+	// file is when nil, HashedFileName fits most bundlers.
+	Hashed func(name string) bool
+
+	// HashedMaxAge is the max-age sent for hashed files, defaults to one
+	// year
+	HashedMaxAge time.Duration
+
+	// SPAFallback serves the root index file for unknown paths, so single
+	// page apps can handle their own routing
+	SPAFallback bool
+}
+
+// Static serves the files in fsys, for instance an embed.FS, for GET and
+// HEAD requests whose path starts with prefix. Range and conditional
+// requests are handled by http.ServeContent.
+func (r *Router) Static(prefix string, fsys fs.FS, opts StaticOptions) *Route {
+	if opts.IndexFile == "" {
+		opts.IndexFile = defaultIndexFile
+	}
+
+	if opts.HashedMaxAge == 0 {
+		opts.HashedMaxAge = defaultHashedMaxAge
+	}
+
+	handler := &staticHandler{fsys, opts}
+	staticpath := path.Join(prefix, "*"+staticParamKey)
+
+	return r.Match([]string{http.MethodGet, http.MethodHead}, staticpath, handler)
+}
+
+type staticHandler struct {
+	fsys fs.FS
+	opts StaticOptions
+}
+
+func (h *staticHandler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	filepath, _ := GetRouteParamsFromRequest(req).GetString(staticParamKey)
+	name := strings.TrimPrefix(path.Clean("/"+filepath), "/")
+
+	if name == "" {
+		name = "."
+	}
+
+	if h.serveFile(res, req, name) {
+		return
+	}
+
+	if h.opts.SPAFallback && h.serveFile(res, req, h.opts.IndexFile) {
+		return
+	}
+
+	http.NotFound(res, req)
+}
+
+// serveFile serves the named file, or the index file if it is a directory,
+// returning false if there is nothing to serve
+func (h *staticHandler) serveFile(res http.ResponseWriter, req *http.Request, name string) bool {
+	info, err := fs.Stat(h.fsys, name)
+
+	if err != nil {
+		return false
+	}
+
+	if info.IsDir() {
+		// Relative links of the index file resolve against the directory
+		if original := getOriginalPathFromRequest(req); !strings.HasSuffix(original, "/") {
+			localRedirect(res, req, path.Base(original)+"/")
+			return true
+		}
+
+		name = path.Join(name, h.opts.IndexFile)
+
+		if info, err = fs.Stat(h.fsys, name); err != nil || info.IsDir() {
+			return false
+		}
+	}
+
+	servedname := name
+
+	if h.opts.Precompressed {
+		servedname = h.negotiatePrecompressed(res, req, name)
+	}
+
+	file, err := h.fsys.Open(servedname)
+
+	if err != nil {
+		return false
+	}
+
+	defer file.Close()
+
+	content, err := toReadSeeker(file)
+
+	if err != nil {
+		return false
+	}
+
+	if h.opts.Hashed != nil && h.opts.Hashed(name) {
+		maxage := strconv.Itoa(int(h.opts.HashedMaxAge.Seconds()))
+		res.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%s, immutable", maxage))
+	}
+
+	if ctype := mime.TypeByExtension(path.Ext(name)); ctype != "" {
+		res.Header().Set("Content-Type", ctype)
+	}
+
+	http.ServeContent(res, req, name, info.ModTime(), content)
+	return true
+}
+
+// localRedirect redirects to target relative to the request path, as
+// http.FileServer does, so it works under any prefix
+func localRedirect(res http.ResponseWriter, req *http.Request, target string) {
+	if req.URL.RawQuery != "" {
+		target += "?" + req.URL.RawQuery
+	}
+
+	res.Header().Set("Location", target)
+	res.WriteHeader(http.StatusMovedPermanently)
+}
+
+// negotiatePrecompressed returns the name of the best precompressed sibling
+// accepted by the client, setting encoding headers, or name if none fits
+func (h *staticHandler) negotiatePrecompressed(res http.ResponseWriter, req *http.Request, name string) string {
+	res.Header().Add("Vary", "Accept-Encoding")
+	accept := req.Header.Get("Accept-Encoding")
+
+	for _, p := range precompressedEncodings {
+		if !acceptsEncoding(accept, p.encoding) {
+			continue
+		}
+
+		if info, err := fs.Stat(h.fsys, name+p.extension); err == nil && !info.IsDir() {
+			res.Header().Set("Content-Encoding", p.encoding)
+			return name + p.extension
+		}
+	}
+
+	return name
+}
+
+// acceptsEncoding reports whether the Accept-Encoding header value accepts
+// the given encoding with a non zero quality
+func acceptsEncoding(accept string, encoding string) bool {
+	return encodingQuality(accept, encoding) > 0
+}
+
+// encodingQuality returns the quality the Accept-Encoding header value gives
+// to encoding, an explicit entry wins over "*", 0 when it is not accepted
+func encodingQuality(accept string, encoding string) float64 {
+	quality, wildcard := -1.0, -1.0
+
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		coding := strings.TrimSpace(fields[0])
+
+		if !strings.EqualFold(coding, encoding) && coding != "*" {
+			continue
+		}
+
+		q := 1.0
+
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+
+			if v, ok := strings.CutPrefix(param, "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		if coding == "*" {
+			wildcard = q
+		} else {
+			quality = q
+		}
+	}
+
+	if quality < 0 {
+		quality = wildcard
+	}
+
+	return max(quality, 0)
+}
+
+func toReadSeeker(file fs.File) (io.ReadSeeker, error) {
+	if rs, ok := file.(io.ReadSeeker); ok {
+		return rs, nil
+	}
+
+	content, err := io.ReadAll(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(content), nil
+}
@@ -0,0 +1,158 @@
+package badger_test
+
+import (
+	"net/http"
+	"testing"
+	"testing/fstest"
+
+	"github.com/hugoluchessi/badger"
+)
+
+func StaticFS() fstest.MapFS {
+	return fstest.MapFS{
+		"index.html":            {Data: []byte("<html>index</html>")},
+		"css/main.css":          {Data: []byte("body {}")},
+		"js/app.3f9a2c1d.js":    {Data: []byte("console.log(1)")},
+		"js/app.3f9a2c1d.js.gz": {Data: []byte("gzipped")},
+		"docs/index.html":       {Data: []byte("<html>docs</html>")},
+	}
+}
+
+func TestStaticServesFiles(t *testing.T) {
+	mux := badger.NewMux()
+	mux.AddRouter(RouterBasePath1).Static("assets", StaticFS(), badger.StaticOptions{})
+
+	res := ServeRequest(mux, GET, "/v1/assets/css/main.css", nil, nil)
+
+	if res.Code != http.StatusOK || res.Body.String() != "body {}" {
+		t.Errorf("Test failed, unexpected response %d '%s'.", res.Code, res.Body.String())
+	}
+
+	AssertHeader(t, res, "Content-Type", "text/css; charset=utf-8")
+	AssertHeader(t, res, "Cache-Control", "")
+}
+
+func TestStaticServesIndexFiles(t *testing.T) {
+	mux := badger.NewMux()
+	mux.AddRouter(RouterBasePath1).Static("assets", StaticFS(), badger.StaticOptions{})
+
+	if res := ServeRequest(mux, GET, "/v1/assets/", nil, nil); res.Body.String() != "<html>index</html>" {
+		t.Errorf("Test failed, expected root index got '%s'.", res.Body.String())
+	}
+
+	if res := ServeRequest(mux, GET, "/v1/assets/docs/", nil, nil); res.Body.String() != "<html>docs</html>" {
+		t.Errorf("Test failed, expected docs index got '%s'.", res.Body.String())
+	}
+}
+
+func TestStaticNotFound(t *testing.T) {
+	mux := badger.NewMux()
+	mux.AddRouter(RouterBasePath1).Static("assets", StaticFS(), badger.StaticOptions{})
+
+	if res := ServeRequest(mux, GET, "/v1/assets/nope.css", nil, nil); res.Code != http.StatusNotFound {
+		t.Errorf("Test failed, expected status %d got %d.", http.StatusNotFound, res.Code)
+	}
+}
+
+func TestStaticSPAFallback(t *testing.T) {
+	mux := badger.NewMux()
+	mux.AddRouter(RouterBasePath1).Static("app", StaticFS(), badger.StaticOptions{SPAFallback: true})
+
+	res := ServeRequest(mux, GET, "/v1/app/users/42", nil, nil)
+
+	if res.Code != http.StatusOK || res.Body.String() != "<html>index</html>" {
+		t.Errorf("Test failed, unexpected response %d '%s'.", res.Code, res.Body.String())
+	}
+}
+
+func TestStaticHashedFilesCacheHeaders(t *testing.T) {
+	mux := badger.NewMux()
+	mux.AddRouter(RouterBasePath1).Static("assets", StaticFS(), badger.StaticOptions{Hashed: badger.HashedFileName})
+
+	res := ServeRequest(mux, GET, "/v1/assets/js/app.3f9a2c1d.js", nil, nil)
+
+	AssertHeader(t, res, "Cache-Control", "public, max-age=31536000, immutable")
+}
+
+func TestStaticHashedFilesOptIn(t *testing.T) {
+	mux := badger.NewMux()
+	mux.AddRouter(RouterBasePath1).Static("assets", StaticFS(), badger.StaticOptions{})
+
+	res := ServeRequest(mux, GET, "/v1/assets/js/app.3f9a2c1d.js", nil, nil)
+
+	AssertHeader(t, res, "Cache-Control", "")
+}
+
+func TestHashedFileName(t *testing.T) {
+	names := map[string]bool{
+		"app.3f9a2c1d.js":            true,
+		"chunk-5d41402abc4b2a76.css": true,
+		"report-20240101.pdf":        false,
+		"invoice_12345678.csv":       false,
+		"main.css":                   false,
+	}
+
+	for name, expected := range names {
+		if badger.HashedFileName(name) != expected {
+			t.Errorf("Test failed, expected '%s' hashed to be %t.", name, expected)
+		}
+	}
+}
+
+func TestStaticRedirectsDirectories(t *testing.T) {
+	mux := badger.NewMux()
+	mux.AddRouter(RouterBasePath1).Static("assets", StaticFS(), badger.StaticOptions{})
+
+	for url, location := range map[string]string{"/v1/assets/docs": "docs/", "/v1/assets": "assets/", "/v1/assets/docs?x=1": "docs/?x=1"} {
+		res := ServeRequest(mux, GET, url, nil, nil)
+
+		if res.Code != http.StatusMovedPermanently || res.Header().Get("Location") != location {
+			t.Errorf("Test failed, expected '%s' to redirect to '%s' got %d '%s'.", url, location, res.Code, res.Header().Get("Location"))
+		}
+	}
+}
+
+func TestStaticPrecompressed(t *testing.T) {
+	mux := badger.NewMux()
+	mux.AddRouter(RouterBasePath1).Static("assets", StaticFS(), badger.StaticOptions{Precompressed: true})
+
+	res := ServeRequest(mux, GET, "/v1/assets/js/app.3f9a2c1d.js", nil, map[string]string{"Accept-Encoding": "br;q=1, gzip"})
+
+	if res.Body.String() != "gzipped" {
+		t.Errorf("Test failed, expected precompressed content got '%s'.", res.Body.String())
+	}
+
+	AssertHeader(t, res, "Content-Encoding", "gzip")
+	AssertHeader(t, res, "Content-Type", "text/javascript; charset=utf-8")
+	AssertHeader(t, res, "Vary", "Accept-Encoding")
+
+	res = ServeRequest(mux, GET, "/v1/assets/js/app.3f9a2c1d.js", nil, map[string]string{"Accept-Encoding": "gzip;q=0"})
+
+	if res.Body.String() != "console.log(1)" {
+		t.Errorf("Test failed, expected original content got '%s'.", res.Body.String())
+	}
+
+	AssertHeader(t, res, "Content-Encoding", "")
+}
+
+func TestStaticRangeRequests(t *testing.T) {
+	mux := badger.NewMux()
+	mux.AddRouter(RouterBasePath1).Static("assets", StaticFS(), badger.StaticOptions{})
+
+	res := ServeRequest(mux, GET, "/v1/assets/css/main.css", nil, map[string]string{"Range": "bytes=0-3"})
+
+	if res.Code != http.StatusPartialContent || res.Body.String() != "body" {
+		t.Errorf("Test failed, unexpected response %d '%s'.", res.Code, res.Body.String())
+	}
+}
+
+func TestStaticHead(t *testing.T) {
+	mux := badger.NewMux()
+	mux.AddRouter(RouterBasePath1).Static("assets", StaticFS(), badger.StaticOptions{})
+
+	res := ServeRequest(mux, "HEAD", "/v1/assets/css/main.css", nil, nil)
+
+	if res.Code != http.StatusOK || res.Body.Len() != 0 {
+		t.Errorf("Test failed, unexpected response %d '%s'.", res.Code, res.Body.String())
+	}
+}
@@ -0,0 +1,257 @@
+package badger
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+var timeoutPolicyKey = NewMetaKey[*Timeout]("timeout")
+
+// Timeout limits how long the handlers of a router or route may run. The
+// request context of the handler gets a deadline, and once it passes the
+// timeout response is sent unless the handler already started writing, in
+// which case it is left to finish. Writes after the timeout fail with
+// http.ErrHandlerTimeout. Middlewares run outside the deadline, they see the
+// timeout response as the handler response.
+//
+// Handlers run in their own goroutine, their panics are raised again as a
+// *PanicError carrying the handler stack.
+type Timeout struct {
+	// Duration is the deadline of the handler
+	Duration time.Duration
+	// Status of the timeout response, defaults to 503
+	Status int
+	// Handler writes the timeout response, defaults to the route error
+	// handler called with an HTTPError of Status
+	Handler http.Handler
+}
+
+// NewTimeout returns a Timeout of d answering with 503
+func NewTimeout(d time.Duration) *Timeout {
+	return &Timeout{Duration: d}
+}
+
+// SetTimeout sets the timeout of every route of the router, routes may
+// override it with WithTimeout
+func (r *Router) SetTimeout(t *Timeout) {
+	r.WithMeta(timeoutPolicyKey, t)
+}
+
+// WithTimeout sets the timeout of the route and returns it, nil removes the
+// router timeout
+func (r *Route) WithTimeout(t *Timeout) *Route {
+	return r.WithMeta(timeoutPolicyKey, t)
+}
+
+func (t *Timeout) handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithTimeout(req.Context(), t.Duration)
+		defer cancel()
+
+		req = req.WithContext(ctx)
+		tw := &timeoutWriter{w: res, header: res.Header().Clone(), ctx: ctx}
+		done := make(chan struct{})
+		panicked := make(chan interface{}, 1)
+		intime := false
+
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicked <- withStack(p)
+					return
+				}
+
+				close(done)
+			}()
+
+			h.ServeHTTP(tw, req)
+			intime = ctx.Err() == nil
+		}()
+
+		select {
+		case p := <-panicked:
+			panic(p)
+		case <-done:
+		case <-ctx.Done():
+		}
+
+		// Both may be ready at once, handlers done before the deadline are
+		// never cut off
+		select {
+		case p := <-panicked:
+			panic(p)
+		case <-done:
+			if intime {
+				tw.finish()
+				return
+			}
+		default:
+		}
+
+		// Clients going away are not timeouts
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && tw.timeout() {
+			t.respond(res, req)
+			return
+		}
+
+		select {
+		case p := <-panicked:
+			panic(p)
+		case <-done:
+			tw.finish()
+		}
+	})
+}
+
+// withStack wraps panic values in a PanicError with the stack of the
+// current goroutine, which is lost once the panic is raised again from
+// another one
+func withStack(p interface{}) interface{} {
+	if _, ok := p.(*PanicError); ok || p == http.ErrAbortHandler {
+		return p
+	}
+
+	return &PanicError{toError(p), p, debug.Stack()}
+}
+
+func (t *Timeout) respond(res http.ResponseWriter, req *http.Request) {
+	if t.Handler != nil {
+		t.Handler.ServeHTTP(res, req)
+		return
+	}
+
+	status := t.Status
+
+	if status == 0 {
+		status = http.StatusServiceUnavailable
+	}
+
+	handleError(res, req, NewHTTPError(status, ""))
+}
+
+// timeoutWriter keeps the handler headers apart until they are written, so
+// the timeout response does not race with or inherit from the handler
+type timeoutWriter struct {
+	w           http.ResponseWriter
+	header      http.Header
+	ctx         context.Context
+	lock        sync.Mutex
+	wroteheader bool
+	timedout    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+
+	if tw.expired() || tw.wroteheader {
+		return
+	}
+
+	tw.writeHeader(status)
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+
+	if tw.expired() {
+		return 0, http.ErrHandlerTimeout
+	}
+
+	if !tw.wroteheader {
+		tw.writeHeader(http.StatusOK)
+	}
+
+	return tw.w.Write(b)
+}
+
+// Flush sends the response written so far, unless the handler timed out
+func (tw *timeoutWriter) Flush() {
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+
+	if tw.expired() {
+		return
+	}
+
+	if !tw.wroteheader {
+		tw.writeHeader(http.StatusOK)
+	}
+
+	if f, ok := tw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController
+func (tw *timeoutWriter) Unwrap() http.ResponseWriter {
+	return tw.w
+}
+
+func (tw *timeoutWriter) writeHeader(status int) {
+	if status >= 100 && status <= 199 {
+		tw.copyHeader()
+		tw.w.WriteHeader(status)
+		return
+	}
+
+	tw.wroteheader = true
+	tw.copyHeader()
+	tw.w.WriteHeader(status)
+}
+
+func (tw *timeoutWriter) copyHeader() {
+	header := tw.w.Header()
+
+	for key := range header {
+		delete(header, key)
+	}
+
+	for key, values := range tw.header {
+		header[key] = values
+	}
+}
+
+// expired reports whether the handler timed out before starting the
+// response, handlers woken by the deadline may write before timeout is
+// called
+func (tw *timeoutWriter) expired() bool {
+	if !tw.timedout && !tw.wroteheader && errors.Is(tw.ctx.Err(), context.DeadlineExceeded) {
+		tw.timedout = true
+	}
+
+	return tw.timedout
+}
+
+// timeout stops the handler writes, it reports false if the handler already
+// started the response
+func (tw *timeoutWriter) timeout() bool {
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+
+	if tw.wroteheader {
+		return false
+	}
+
+	tw.timedout = true
+	return true
+}
+
+// finish sends the headers of handlers which wrote nothing
+func (tw *timeoutWriter) finish() {
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+
+	if !tw.wroteheader && !tw.timedout {
+		tw.copyHeader()
+	}
+}
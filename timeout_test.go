@@ -0,0 +1,176 @@
+package badger_test
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hugoluchessi/badger"
+)
+
+// LateHandlerFunc writes once the deadline passed, sending the write error
+// to late
+func LateHandlerFunc(late chan error) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("X-Handler", "slow")
+		<-req.Context().Done()
+
+		_, err := fmt.Fprint(res, "late")
+		late <- err
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	late := make(chan error, 1)
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetTimeout(badger.NewTimeout(10 * time.Millisecond))
+	router.Get("slow", LateHandlerFunc(late))
+
+	res := ServeRequest(mux, GET, "/v1/slow", nil, nil)
+
+	if res.Code != http.StatusServiceUnavailable || res.Header().Get("X-Handler") != "" {
+		t.Errorf("Test failed, expected a clean 503 got %d '%v'.", res.Code, res.Header())
+	}
+
+	if err := <-late; err != http.ErrHandlerTimeout {
+		t.Errorf("Test failed, expected late write to fail got '%v'.", err)
+	}
+
+	if res.Body.String() != "Service Unavailable\n" {
+		t.Errorf("Test failed, unexpected body '%s'.", res.Body.String())
+	}
+}
+
+func TestTimeoutCustomResponse(t *testing.T) {
+	timeout := &badger.Timeout{
+		Duration: 10 * time.Millisecond,
+		Status:   http.StatusGatewayTimeout,
+	}
+
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetTimeout(timeout)
+	router.Get("slow", LateHandlerFunc(make(chan error, 2)))
+
+	res := ServeRequest(mux, GET, "/v1/slow", nil, nil)
+
+	if res.Code != http.StatusGatewayTimeout {
+		t.Errorf("Test failed, expected status 504 got %d.", res.Code)
+	}
+
+	timeout.Handler = http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusGatewayTimeout)
+		fmt.Fprint(res, `{"error":"timeout"}`)
+	})
+
+	res = ServeRequest(mux, GET, "/v1/slow", nil, nil)
+
+	if res.Code != http.StatusGatewayTimeout || res.Body.String() != `{"error":"timeout"}` {
+		t.Errorf("Test failed, unexpected response %d '%s'.", res.Code, res.Body.String())
+	}
+}
+
+func TestTimeoutStartedResponse(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetTimeout(badger.NewTimeout(10 * time.Millisecond))
+	router.Get("started", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(res, "started ")
+		<-req.Context().Done()
+		fmt.Fprint(res, "finished")
+	}))
+
+	res := ServeRequest(mux, GET, "/v1/started", nil, nil)
+
+	if res.Code != http.StatusOK || res.Body.String() != "started finished" {
+		t.Errorf("Test failed, expected started response to finish got %d '%s'.", res.Code, res.Body.String())
+	}
+}
+
+func TestTimeoutKeepsHeaders(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetTimeout(badger.NewTimeout(time.Second))
+	router.Get("fast", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("X-Handler", "fast")
+		res.WriteHeader(http.StatusAccepted)
+	}))
+	router.Get("empty", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("X-Handler", "empty")
+	}))
+
+	res := ServeRequest(mux, GET, "/v1/fast", nil, nil)
+
+	if res.Code != http.StatusAccepted || res.Header().Get("X-Handler") != "fast" {
+		t.Errorf("Test failed, unexpected response %d '%v'.", res.Code, res.Header())
+	}
+
+	res = ServeRequest(mux, GET, "/v1/empty", nil, nil)
+
+	if res.Code != http.StatusOK || res.Header().Get("X-Handler") != "empty" {
+		t.Errorf("Test failed, unexpected response %d '%v'.", res.Code, res.Header())
+	}
+}
+
+func TestTimeoutRouteOverride(t *testing.T) {
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetTimeout(badger.NewTimeout(time.Second))
+	router.Get("unlimited", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if _, ok := req.Context().Deadline(); ok {
+			res.WriteHeader(http.StatusInternalServerError)
+		}
+	})).WithTimeout(nil)
+
+	res := ServeRequest(mux, GET, "/v1/unlimited", nil, nil)
+
+	if res.Code != http.StatusOK {
+		t.Errorf("Test failed, expected no deadline got %d.", res.Code)
+	}
+}
+
+func TestTimeoutPanic(t *testing.T) {
+	var recovered *badger.PanicError
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetTimeout(badger.NewTimeout(time.Second))
+	router.Get("panic", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		panic(ErrBoom)
+	}))
+	mux.PanicHandler = badger.NewRecovery(func(req *http.Request, err *badger.PanicError) {
+		recovered = err
+	}).PanicHandler
+
+	if res := ServeRequest(mux, GET, "/v1/panic", nil, nil); res.Code != http.StatusInternalServerError {
+		t.Errorf("Test failed, expected the panic to be recovered got %d.", res.Code)
+	}
+
+	if recovered == nil || recovered.Value != ErrBoom || !strings.Contains(string(recovered.Stack), "timeout_test.go") {
+		t.Errorf("Test failed, expected the handler stack got '%v'.", recovered)
+	}
+}
+
+func TestTimeoutMiddlewaresSeeResponse(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.SetTimeout(badger.NewTimeout(10 * time.Millisecond))
+	router.Use(badger.NewAccessLog(slog.New(slog.NewJSONHandler(buffer, nil))).Middleware)
+
+	router.Get("slow", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		<-req.Context().Done()
+	}))
+
+	if res := ServeRequest(mux, GET, "/v1/slow", nil, nil); res.Code != http.StatusServiceUnavailable {
+		t.Errorf("Test failed, expected status 503 got %d.", res.Code)
+	}
+
+	if !strings.Contains(buffer.String(), `"status":503`) {
+		t.Errorf("Test failed, expected the timeout to be logged got '%s'.", buffer.String())
+	}
+}
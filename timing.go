@@ -0,0 +1,240 @@
+package badger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ServerTimingHeader is the response header holding the request timeline
+const ServerTimingHeader = "Server-Timing"
+
+type timelineKey struct{}
+
+// Timing instruments every middleware and final handler of the routes,
+// recording a per request Timeline. Set it on Mux.Timing to enable it.
+type Timing struct {
+	// Header sends the timeline in the Server-Timing response header, as
+	// measured when the response header is written
+	Header bool
+	// Threshold logs the timeline of requests taking at least this long at
+	// debug level, 0 disables logging
+	Threshold time.Duration
+	// Logger receives the slow requests, defaults to slog.Default()
+	Logger *slog.Logger
+}
+
+// NewTiming returns a Timing sending the Server-Timing header
+func NewTiming() *Timing {
+	return &Timing{Header: true}
+}
+
+// TimingEntry is a timed step of a request, Duration excludes the time
+// spent in the next middlewares
+type TimingEntry struct {
+	// Name is the Server-Timing metric name, mw0, mw1, ... for the
+	// middlewares in order and handler for the final handler
+	Name string
+	// Description is the middleware function name
+	Description string
+	Duration    time.Duration
+}
+
+// Timeline records the time spent in each layer of a request
+type Timeline struct {
+	lock   sync.Mutex
+	start  time.Time
+	layers []*timelineLayer
+	marks  []TimingEntry
+}
+
+type timelineLayer struct {
+	name  string
+	desc  string
+	start time.Time
+	end   time.Time
+}
+
+// TimelineFromContext returns the timeline of the request, nil when Timing
+// is disabled
+func TimelineFromContext(ctx context.Context) *Timeline {
+	tl, _ := ctx.Value(timelineKey{}).(*Timeline)
+	return tl
+}
+
+// Add records a custom step, such as a database query
+func (tl *Timeline) Add(name string, description string, duration time.Duration) {
+	tl.lock.Lock()
+	defer tl.lock.Unlock()
+
+	tl.marks = append(tl.marks, TimingEntry{name, description, duration})
+}
+
+// Entries returns the steps recorded so far, layers still running are
+// measured until now
+func (tl *Timeline) Entries() []TimingEntry {
+	tl.lock.Lock()
+	defer tl.lock.Unlock()
+
+	now := time.Now()
+	entries := make([]TimingEntry, 0, len(tl.layers)+len(tl.marks))
+
+	for i, layer := range tl.layers {
+		duration := layer.elapsed(now)
+
+		// Layers are nested, the next one runs inside this one
+		if i+1 < len(tl.layers) {
+			duration -= tl.layers[i+1].elapsed(now)
+		}
+
+		entries = append(entries, TimingEntry{layer.name, layer.desc, duration})
+	}
+
+	return append(entries, tl.marks...)
+}
+
+// Total returns the time elapsed since the request started
+func (tl *Timeline) Total() time.Duration {
+	return time.Since(tl.start)
+}
+
+func (tl *Timeline) enter(name string, desc string) *timelineLayer {
+	tl.lock.Lock()
+	defer tl.lock.Unlock()
+
+	layer := &timelineLayer{name, desc, time.Now(), time.Time{}}
+	tl.layers = append(tl.layers, layer)
+
+	return layer
+}
+
+func (tl *Timeline) leave(layer *timelineLayer) {
+	tl.lock.Lock()
+	defer tl.lock.Unlock()
+
+	layer.end = time.Now()
+}
+
+func (layer *timelineLayer) elapsed(now time.Time) time.Duration {
+	if layer.end.IsZero() {
+		return now.Sub(layer.start)
+	}
+
+	return layer.end.Sub(layer.start)
+}
+
+// serverTiming formats the entries as a Server-Timing header value
+func serverTiming(entries []TimingEntry, total time.Duration) string {
+	parts := make([]string, 0, len(entries)+1)
+
+	for _, entry := range entries {
+		part := entry.Name
+
+		if entry.Description != "" {
+			part += ";desc=" + strconv.Quote(entry.Description)
+		}
+
+		parts = append(parts, part+";dur="+formatMilliseconds(entry.Duration))
+	}
+
+	parts = append(parts, "total;dur="+formatMilliseconds(total))
+
+	return strings.Join(parts, ", ")
+}
+
+func formatMilliseconds(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)
+}
+
+// instrument wraps every middleware and the final handler of a route with
+// timers, the handler is wrapped first and middlewares in the order they
+// are applied
+func (t *Timing) instrument(handler http.Handler, middlewares []middleware) http.Handler {
+	handler = t.layer("handler", "", handler)
+
+	for i, middleware := range middlewares {
+		handler = t.layer(fmt.Sprintf("mw%d", i), middlewareName(middleware), middleware(handler))
+	}
+
+	return handler
+}
+
+func (t *Timing) layer(name string, desc string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		tl := TimelineFromContext(req.Context())
+
+		if tl == nil {
+			h.ServeHTTP(res, req)
+			return
+		}
+
+		layer := tl.enter(name, desc)
+		defer tl.leave(layer)
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+// start creates the request timeline, it wraps the instrumented route
+func (t *Timing) start(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		tl := &Timeline{start: time.Now()}
+		req = req.WithContext(context.WithValue(req.Context(), timelineKey{}, tl))
+
+		if !t.Header {
+			h.ServeHTTP(res, req)
+		} else {
+			header := res.Header()
+			setHeader := func(int) {
+				header.Set(ServerTimingHeader, serverTiming(tl.Entries(), tl.Total()))
+			}
+
+			rw := WrapResponseWriter(res, ResponseWriterHooks{BeforeWriteHeader: setHeader})
+			h.ServeHTTP(rw, req)
+
+			// The header of empty responses is written once the handler returns
+			if !rw.WroteHeader() {
+				setHeader(http.StatusOK)
+			}
+		}
+
+		total := tl.Total()
+
+		if t.Threshold <= 0 || total < t.Threshold {
+			return
+		}
+
+		logger := t.Logger
+
+		if logger == nil {
+			logger = slog.Default()
+		}
+
+		route, _ := RouteFromContext(req.Context())
+		attrs := []slog.Attr{
+			slog.String("method", req.Method),
+			slog.String("route", route.Pattern),
+			slog.Duration("duration", total),
+		}
+
+		for _, entry := range tl.Entries() {
+			attrs = append(attrs, slog.Group(entry.Name, slog.String("desc", entry.Description), slog.Duration("duration", entry.Duration)))
+		}
+
+		logger.LogAttrs(req.Context(), slog.LevelDebug, "slow request", attrs...)
+	})
+}
+
+func middlewareName(m middleware) string {
+	name := runtime.FuncForPC(reflect.ValueOf(m).Pointer()).Name()
+	name = strings.TrimSuffix(name, "-fm")
+
+	return name[strings.LastIndex(name, "/")+1:]
+}
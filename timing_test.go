@@ -0,0 +1,139 @@
+package badger_test
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hugoluchessi/badger"
+)
+
+func SlowMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		h.ServeHTTP(res, req)
+	})
+}
+
+func PassMiddleware(h http.Handler) http.Handler {
+	return h
+}
+
+var serverTimingDurRegexp = regexp.MustCompile(`^([a-z0-9]+)(?:;desc="([^"]*)")?;dur=([0-9.]+)$`)
+
+func ParseServerTiming(t *testing.T, value string) map[string]float64 {
+	t.Helper()
+
+	timings := map[string]float64{}
+
+	for _, part := range strings.Split(value, ", ") {
+		matches := serverTimingDurRegexp.FindStringSubmatch(part)
+
+		if matches == nil {
+			t.Fatalf("Test failed, invalid Server-Timing entry '%s'.", part)
+		}
+
+		timings[matches[1]], _ = strconv.ParseFloat(matches[3], 64)
+	}
+
+	return timings
+}
+
+func TestServerTimingHeader(t *testing.T) {
+	mux := badger.NewMux()
+	mux.Timing = badger.NewTiming()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Use(PassMiddleware)
+	router.Use(SlowMiddleware)
+	router.Get("write", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		badger.TimelineFromContext(req.Context()).Add("db", "query", 5*time.Millisecond)
+		fmt.Fprint(res, "done")
+	}))
+	router.Get("empty", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {}))
+
+	for _, url := range []string{"/v1/write", "/v1/empty"} {
+		res := ServeRequest(mux, GET, url, nil, nil)
+
+		header := res.Header().Get(badger.ServerTimingHeader)
+
+		if !strings.Contains(header, `mw1;desc="badger_test.SlowMiddleware"`) || !strings.Contains(header, `mw0;desc="badger_test.PassMiddleware"`) {
+			t.Errorf("Test failed, expected middleware names in '%s'.", header)
+		}
+
+		timings := ParseServerTiming(t, header)
+
+		if timings["mw1"] < 20 || timings["mw0"] >= 20 || timings["handler"] >= 20 || timings["total"] < 20 {
+			t.Errorf("Test failed, expected the slow middleware to be blamed '%s'.", header)
+		}
+	}
+}
+
+func TestServerTimingCustomEntry(t *testing.T) {
+	mux := badger.NewMux()
+	mux.Timing = badger.NewTiming()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Use(PassMiddleware)
+	router.Use(SlowMiddleware)
+	router.Get("write", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		badger.TimelineFromContext(req.Context()).Add("db", "query", 5*time.Millisecond)
+		fmt.Fprint(res, "done")
+	}))
+
+	res := ServeRequest(mux, GET, "/v1/write", nil, nil)
+
+	if header := res.Header().Get(badger.ServerTimingHeader); !strings.Contains(header, `db;desc="query";dur=5.000`) {
+		t.Errorf("Test failed, expected custom entry in '%s'.", header)
+	}
+}
+
+func TestTimingLogsSlowRequests(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	mux := badger.NewMux()
+	mux.Timing = &badger.Timing{Threshold: 10 * time.Millisecond, Logger: logger}
+	router := mux.AddRouter(RouterBasePath1)
+	router.Use(PassMiddleware)
+	router.Use(SlowMiddleware)
+	router.Get("empty", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {}))
+
+	res := ServeRequest(mux, GET, "/v1/empty", nil, nil)
+
+	if header := res.Header().Get(badger.ServerTimingHeader); header != "" {
+		t.Errorf("Test failed, expected no header got '%s'.", header)
+	}
+
+	line := buf.String()
+
+	if !strings.Contains(line, "level=DEBUG msg=\"slow request\"") || !strings.Contains(line, "route=/v1/empty/") || !strings.Contains(line, "mw1.desc=badger_test.SlowMiddleware") {
+		t.Errorf("Test failed, unexpected log '%s'.", line)
+	}
+
+	buf.Reset()
+	mux = badger.NewMux()
+	mux.Timing = &badger.Timing{Threshold: time.Hour, Logger: logger}
+	router = mux.AddRouter(RouterBasePath1)
+	router.Use(PassMiddleware)
+	router.Use(SlowMiddleware)
+	router.Get("empty", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {}))
+
+	ServeRequest(mux, GET, "/v1/empty", nil, nil)
+
+	if buf.Len() != 0 {
+		t.Errorf("Test failed, expected fast request not to be logged got '%s'.", buf.String())
+	}
+}
+
+func TestTimelineWithoutTiming(t *testing.T) {
+	req, _ := http.NewRequest(GET, "/", nil)
+
+	if tl := badger.TimelineFromContext(req.Context()); tl != nil {
+		t.Error("Test failed, expected no timeline.")
+	}
+}
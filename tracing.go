@@ -0,0 +1,322 @@
+package badger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Trace context headers
+const (
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
+	B3Header          = "b3"
+	B3TraceIDHeader   = "X-B3-TraceId"
+	B3SpanIDHeader    = "X-B3-SpanId"
+	B3SampledHeader   = "X-B3-Sampled"
+	B3FlagsHeader     = "X-B3-Flags"
+)
+
+// TraceFlagsSampled is the trace flag set when the trace is recorded
+const TraceFlagsSampled byte = 0x01
+
+type spanContextKey struct{}
+
+// TraceID identifies a trace
+type TraceID [16]byte
+
+// SpanID identifies a span within a trace
+type SpanID [8]byte
+
+// IsValid reports whether the id is not all zeros
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid reports whether the id is not all zeros
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanContext identifies a span and carries the trace options propagated
+// to other services
+type SpanContext struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	TraceFlags byte
+	// TraceState is the vendor specific tracestate header value
+	TraceState string
+	// Remote is true when the span context was received from a client
+	Remote bool
+}
+
+// IsValid reports whether the trace and span ids are set
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// IsSampled reports whether the trace is recorded
+func (sc SpanContext) IsSampled() bool {
+	return sc.TraceFlags&TraceFlagsSampled != 0
+}
+
+// TraceParent returns the traceparent header value of the span context
+func (sc SpanContext) TraceParent() string {
+	return fmt.Sprintf("00-%s-%s-%02x", sc.TraceID, sc.SpanID, sc.TraceFlags)
+}
+
+// Span is a finished request span handed to a SpanExporter
+type Span struct {
+	// Name is the method and the matched route pattern, such as
+	// "GET /v1/users/:id/"
+	Name         string
+	SpanContext  SpanContext
+	ParentSpanID SpanID
+	Start        time.Time
+	End          time.Time
+	Attributes   map[string]interface{}
+	// Status is the HTTP response status
+	Status int
+}
+
+// Tracing is a middleware creating a span per request, continuing the trace
+// of the incoming traceparent header, or of the B3 headers if enabled. The
+// span context is available through SpanContextFromContext.
+type Tracing struct {
+	// Exporter receives the sampled spans once the request is served, from
+	// the request goroutine. Use a BatchExporter for remote backends.
+	Exporter SpanExporter
+	// B3 also reads the trace from B3 headers when there is no traceparent
+	B3 bool
+	// Logger receives the export errors, defaults to slog.Default()
+	Logger *slog.Logger
+}
+
+// NewTracing returns a Tracing exporting spans to exporter
+func NewTracing(exporter SpanExporter) *Tracing {
+	return &Tracing{Exporter: exporter}
+}
+
+// Middleware creates the request span around the given handler
+func (t *Tracing) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		parent, ok := ParseTraceParent(req.Header.Get(TraceParentHeader))
+
+		if ok {
+			parent.TraceState = strings.Join(req.Header.Values(TraceStateHeader), ",")
+		} else if t.B3 {
+			parent, ok = parseB3(req.Header)
+		}
+
+		sc := SpanContext{TraceID: parent.TraceID, SpanID: newSpanID(), TraceFlags: parent.TraceFlags, TraceState: parent.TraceState}
+
+		if !ok {
+			sc = SpanContext{TraceID: newTraceID(), SpanID: sc.SpanID, TraceFlags: TraceFlagsSampled}
+		}
+
+		route, _ := RouteFromContext(req.Context())
+		start := time.Now()
+		rw := trackResponseWriter(res)
+
+		req = req.WithContext(context.WithValue(req.Context(), spanContextKey{}, sc))
+		h.ServeHTTP(rw, req)
+
+		if !sc.IsSampled() || t.Exporter == nil {
+			return
+		}
+
+		status := rw.Status()
+
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		span := Span{
+			Name:         strings.TrimSpace(req.Method + " " + route.Pattern),
+			SpanContext:  sc,
+			ParentSpanID: parent.SpanID,
+			Start:        start,
+			End:          time.Now(),
+			Attributes: map[string]interface{}{
+				"http.request.method":       req.Method,
+				"http.route":                route.Pattern,
+				"url.path":                  getOriginalPathFromRequest(req),
+				"http.response.status_code": status,
+			},
+			Status: status,
+		}
+
+		if err := t.Exporter.Export(context.WithoutCancel(req.Context()), []Span{span}); err != nil {
+			logger := t.Logger
+
+			if logger == nil {
+				logger = slog.Default()
+			}
+
+			logger.WarnContext(req.Context(), "span export failed", slog.String("error", err.Error()))
+		}
+	})
+}
+
+// SpanContextFromContext returns the span context of the request span
+// created by Tracing, ok is false if there is none
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc, ok
+}
+
+// ParseTraceParent parses a traceparent header value, ok is false if it is
+// invalid. The returned span context is the remote parent span.
+func ParseTraceParent(value string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+
+	if len(parts) < 4 {
+		return SpanContext{}, false
+	}
+
+	version, err := hex.DecodeString(parts[0])
+
+	// Future versions may append fields, version 00 may not
+	if err != nil || len(version) != 1 || version[0] == 0xff || version[0] == 0 && len(parts) != 4 {
+		return SpanContext{}, false
+	}
+
+	sc := SpanContext{Remote: true}
+	flags := []byte{0}
+
+	if !decodeHexID(sc.TraceID[:], parts[1]) || !decodeHexID(sc.SpanID[:], parts[2]) || !decodeHexID(flags, parts[3]) || !sc.IsValid() {
+		return SpanContext{}, false
+	}
+
+	sc.TraceFlags = flags[0]
+
+	return sc, true
+}
+
+func parseB3(header http.Header) (SpanContext, bool) {
+	traceid, spanid, sampled := header.Get(B3TraceIDHeader), header.Get(B3SpanIDHeader), header.Get(B3SampledHeader)
+
+	if header.Get(B3FlagsHeader) == "1" {
+		sampled = "d"
+	}
+
+	if single := header.Get(B3Header); single != "" {
+		parts := strings.Split(single, "-")
+
+		if len(parts) < 2 {
+			return SpanContext{}, false
+		}
+
+		traceid, spanid, sampled = parts[0], parts[1], ""
+
+		if len(parts) > 2 {
+			sampled = parts[2]
+		}
+	}
+
+	// 64 bit trace ids are left padded
+	if len(traceid) == 16 {
+		traceid = strings.Repeat("0", 16) + traceid
+	}
+
+	sc := SpanContext{Remote: true}
+
+	if !decodeHexID(sc.TraceID[:], traceid) || !decodeHexID(sc.SpanID[:], spanid) || !sc.IsValid() {
+		return SpanContext{}, false
+	}
+
+	// An absent sampling decision is taken here
+	if sampled == "" || sampled == "1" || sampled == "d" || sampled == "true" {
+		sc.TraceFlags = TraceFlagsSampled
+	}
+
+	return sc, true
+}
+
+func decodeHexID(dst []byte, value string) bool {
+	// Ids are lowercase hex only
+	if len(value) != hex.EncodedLen(len(dst)) || strings.ToLower(value) != value {
+		return false
+	}
+
+	_, err := hex.Decode(dst, []byte(value))
+	return err == nil
+}
+
+// TraceTransport is an http.RoundTripper propagating the span context found
+// in the outgoing request context through the traceparent and tracestate
+// headers, and the b3 header if enabled
+type TraceTransport struct {
+	// Base sends the requests, defaults to http.DefaultTransport
+	Base http.RoundTripper
+	// B3 also sets the single b3 header
+	B3 bool
+}
+
+// RoundTrip sends req with the trace context headers set
+func (t *TraceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	sc, ok := SpanContextFromContext(req.Context())
+
+	if !ok || !sc.IsValid() || req.Header.Get(TraceParentHeader) != "" {
+		return base.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the given request
+	req = req.Clone(req.Context())
+	req.Header.Set(TraceParentHeader, sc.TraceParent())
+
+	if sc.TraceState != "" {
+		req.Header.Set(TraceStateHeader, sc.TraceState)
+	}
+
+	if t.B3 {
+		sampled := "0"
+
+		if sc.IsSampled() {
+			sampled = "1"
+		}
+
+		req.Header.Set(B3Header, fmt.Sprintf("%s-%s-%s", sc.TraceID, sc.SpanID, sampled))
+	}
+
+	return base.RoundTrip(req)
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+
+	for !id.IsValid() {
+		rand.Read(id[:])
+	}
+
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+
+	for !id.IsValid() {
+		rand.Read(id[:])
+	}
+
+	return id
+}
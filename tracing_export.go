@@ -0,0 +1,453 @@
+package badger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// OTLPDefaultEndpoint is the default OTLP/HTTP traces endpoint of a local
+// collector
+const OTLPDefaultEndpoint = "http://localhost:4318/v1/traces"
+
+// OTLPDefaultTimeout is the default time limit of an OTLP export request
+const OTLPDefaultTimeout = 10 * time.Second
+
+// Default BatchExporter limits
+const (
+	DefaultSpanQueueSize     = 2048
+	DefaultSpanBatchSize     = 512
+	DefaultSpanBatchInterval = 5 * time.Second
+)
+
+// ErrSpanQueueFull is returned by BatchExporter when spans are dropped
+// because the queue is full
+var ErrSpanQueueFull = errors.New("span queue full")
+
+// ErrExporterShutdown is returned by BatchExporter once shut down
+var ErrExporterShutdown = errors.New("span exporter shut down")
+
+// SpanExporter sends finished spans to a tracing backend
+type SpanExporter interface {
+	Export(ctx context.Context, spans []Span) error
+}
+
+// InMemoryExporter keeps the exported spans in memory, for tests
+type InMemoryExporter struct {
+	lock  sync.Mutex
+	spans []Span
+}
+
+// NewInMemoryExporter returns an empty InMemoryExporter
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+// Export stores the spans
+func (e *InMemoryExporter) Export(ctx context.Context, spans []Span) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+// Spans returns the exported spans
+func (e *InMemoryExporter) Spans() []Span {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return append([]Span{}, e.spans...)
+}
+
+// Reset removes the exported spans
+func (e *InMemoryExporter) Reset() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.spans = nil
+}
+
+// BatchExporter queues spans and hands them in batches to Exporter from a
+// background goroutine, so requests never wait for the tracing backend.
+// Spans are dropped when the queue is full.
+type BatchExporter struct {
+	// Exporter receives the batches
+	Exporter SpanExporter
+	// QueueSize is the number of spans waiting to be exported, defaults to
+	// DefaultSpanQueueSize
+	QueueSize int
+	// BatchSize is the maximum number of spans per export, defaults to
+	// DefaultSpanBatchSize
+	BatchSize int
+	// Interval is how long spans wait for a batch to fill, defaults to
+	// DefaultSpanBatchInterval
+	Interval time.Duration
+	// Logger receives the export errors, defaults to slog.Default()
+	Logger *slog.Logger
+
+	once     sync.Once
+	queue    chan Span
+	flush    chan chan struct{}
+	shutdown chan struct{}
+	done     chan struct{}
+	stop     sync.Once
+}
+
+// NewBatchExporter returns a BatchExporter with the default limits
+func NewBatchExporter(exporter SpanExporter) *BatchExporter {
+	return &BatchExporter{Exporter: exporter}
+}
+
+// Export queues the spans, it never blocks
+func (b *BatchExporter) Export(ctx context.Context, spans []Span) error {
+	b.start()
+
+	select {
+	case <-b.shutdown:
+		return ErrExporterShutdown
+	default:
+	}
+
+	for _, span := range spans {
+		select {
+		case b.queue <- span:
+		default:
+			return ErrSpanQueueFull
+		}
+	}
+
+	return nil
+}
+
+// Flush exports the queued spans, waiting until it is done or ctx ends
+func (b *BatchExporter) Flush(ctx context.Context) error {
+	b.start()
+	ack := make(chan struct{})
+
+	select {
+	case b.flush <- ack:
+	case <-b.done:
+		return ErrExporterShutdown
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-ack:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown exports the queued spans and stops the background goroutine,
+// waiting until it is done or ctx ends
+func (b *BatchExporter) Shutdown(ctx context.Context) error {
+	b.start()
+	b.stop.Do(func() { close(b.shutdown) })
+
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *BatchExporter) start() {
+	b.once.Do(func() {
+		size, batchsize, interval := b.QueueSize, b.BatchSize, b.Interval
+
+		if size <= 0 {
+			size = DefaultSpanQueueSize
+		}
+
+		if batchsize <= 0 {
+			batchsize = DefaultSpanBatchSize
+		}
+
+		if interval <= 0 {
+			interval = DefaultSpanBatchInterval
+		}
+
+		b.queue = make(chan Span, size)
+		b.flush = make(chan chan struct{})
+		b.shutdown = make(chan struct{})
+		b.done = make(chan struct{})
+
+		go b.run(batchsize, interval)
+	})
+}
+
+func (b *BatchExporter) run(batchsize int, interval time.Duration) {
+	defer close(b.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	batch := make([]Span, 0, batchsize)
+
+	// drain exports every queued span
+	drain := func() {
+		for {
+			select {
+			case span := <-b.queue:
+				if batch = append(batch, span); len(batch) >= batchsize {
+					batch = b.export(batch)
+				}
+			default:
+				batch = b.export(batch)
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case span := <-b.queue:
+			if batch = append(batch, span); len(batch) >= batchsize {
+				batch = b.export(batch)
+			}
+		case <-ticker.C:
+			batch = b.export(batch)
+		case ack := <-b.flush:
+			drain()
+			close(ack)
+		case <-b.shutdown:
+			drain()
+			return
+		}
+	}
+}
+
+// export sends the batch and returns it emptied
+func (b *BatchExporter) export(batch []Span) []Span {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	if err := b.Exporter.Export(context.Background(), batch); err != nil {
+		logger := b.Logger
+
+		if logger == nil {
+			logger = slog.Default()
+		}
+
+		logger.Warn("span export failed", slog.String("error", err.Error()), slog.Int("spans", len(batch)))
+	}
+
+	return batch[:0]
+}
+
+// OTLPExporter posts spans to an OpenTelemetry collector using OTLP/HTTP
+// with JSON encoding. Export waits for the collector, wrap it in a
+// BatchExporter to send spans in the background.
+type OTLPExporter struct {
+	// Endpoint is the traces URL, defaults to OTLPDefaultEndpoint
+	Endpoint string
+	// ServiceName is the service.name resource attribute
+	ServiceName string
+	// Headers are added to every export request, such as authentication
+	Headers map[string]string
+	// Client sends the export requests, defaults to http.DefaultClient
+	Client *http.Client
+	// Timeout limits each export request, defaults to OTLPDefaultTimeout
+	Timeout time.Duration
+}
+
+// NewOTLPExporter returns an OTLPExporter posting to endpoint as service
+func NewOTLPExporter(endpoint string, service string) *OTLPExporter {
+	return &OTLPExporter{Endpoint: endpoint, ServiceName: service}
+}
+
+// Export posts the spans to the collector
+func (e *OTLPExporter) Export(ctx context.Context, spans []Span) error {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(e.payload(spans))
+
+	if err != nil {
+		return err
+	}
+
+	endpoint := e.Endpoint
+
+	if endpoint == "" {
+		endpoint = OTLPDefaultEndpoint
+	}
+
+	timeout := e.Timeout
+
+	if timeout <= 0 {
+		timeout = OTLPDefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for key, value := range e.Headers {
+		req.Header.Set(key, value)
+	}
+
+	client := e.Client
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("otlp export failed with status %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+type otlpValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpStatus struct {
+	Code int `json:"code"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	TraceState        string          `json:"traceState,omitempty"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Flags             uint32          `json:"flags"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	} `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpPayload struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+// OTLP span kind and status codes
+const (
+	otlpSpanKindServer  = 2
+	otlpStatusCodeError = 2
+)
+
+func (e *OTLPExporter) payload(spans []Span) otlpPayload {
+	scope := otlpScopeSpans{Spans: make([]otlpSpan, 0, len(spans))}
+	scope.Scope.Name = "github.com/hugoluchessi/badger"
+
+	for _, span := range spans {
+		s := otlpSpan{
+			TraceID:           span.SpanContext.TraceID.String(),
+			SpanID:            span.SpanContext.SpanID.String(),
+			TraceState:        span.SpanContext.TraceState,
+			Flags:             uint32(span.SpanContext.TraceFlags),
+			Name:              span.Name,
+			Kind:              otlpSpanKindServer,
+			StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+			Attributes:        otlpAttributes(span.Attributes),
+		}
+
+		if span.ParentSpanID.IsValid() {
+			s.ParentSpanID = span.ParentSpanID.String()
+		}
+
+		if span.Status >= http.StatusInternalServerError {
+			s.Status.Code = otlpStatusCodeError
+		}
+
+		scope.Spans = append(scope.Spans, s)
+	}
+
+	resource := otlpResourceSpans{ScopeSpans: []otlpScopeSpans{scope}}
+	resource.Resource.Attributes = otlpAttributes(map[string]interface{}{"service.name": e.ServiceName})
+
+	return otlpPayload{[]otlpResourceSpans{resource}}
+}
+
+func otlpAttributes(attributes map[string]interface{}) []otlpAttribute {
+	keys := make([]string, 0, len(attributes))
+
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	converted := make([]otlpAttribute, 0, len(keys))
+
+	for _, key := range keys {
+		var value otlpValue
+
+		switch v := attributes[key].(type) {
+		case int:
+			i := strconv.Itoa(v)
+			value.IntValue = &i
+		case int64:
+			i := strconv.FormatInt(v, 10)
+			value.IntValue = &i
+		case bool:
+			value.BoolValue = &v
+		default:
+			s := fmt.Sprint(v)
+			value.StringValue = &s
+		}
+
+		converted = append(converted, otlpAttribute{key, value})
+	}
+
+	return converted
+}
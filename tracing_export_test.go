@@ -0,0 +1,204 @@
+package badger_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hugoluchessi/badger"
+)
+
+type OTLPRequest struct {
+	ResourceSpans []struct {
+		Resource struct {
+			Attributes []struct {
+				Key   string `json:"key"`
+				Value struct {
+					StringValue string `json:"stringValue"`
+				} `json:"value"`
+			} `json:"attributes"`
+		} `json:"resource"`
+		ScopeSpans []struct {
+			Spans []struct {
+				TraceID           string `json:"traceId"`
+				SpanID            string `json:"spanId"`
+				ParentSpanID      string `json:"parentSpanId"`
+				Name              string `json:"name"`
+				Kind              int    `json:"kind"`
+				StartTimeUnixNano string `json:"startTimeUnixNano"`
+				Attributes        []struct {
+					Key   string `json:"key"`
+					Value struct {
+						IntValue string `json:"intValue"`
+					} `json:"value"`
+				} `json:"attributes"`
+				Status struct {
+					Code int `json:"code"`
+				} `json:"status"`
+			} `json:"spans"`
+		} `json:"scopeSpans"`
+	} `json:"resourceSpans"`
+}
+
+func NewCollector(t *testing.T, status int, received *OTLPRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.Method != POST || req.URL.Path != "/v1/traces" || req.Header.Get("Content-Type") != "application/json" || req.Header.Get("Authorization") != "token" {
+			t.Errorf("Test failed, unexpected export request '%s %s' '%v'.", req.Method, req.URL.Path, req.Header)
+		}
+
+		json.NewDecoder(req.Body).Decode(received)
+		res.WriteHeader(status)
+	}))
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var received OTLPRequest
+
+	collector := NewCollector(t, http.StatusOK, &received)
+	defer collector.Close()
+
+	exporter := badger.NewOTLPExporter(collector.URL+"/v1/traces", "users")
+	exporter.Headers = map[string]string{"Authorization": "token"}
+
+	sc, _ := badger.ParseTraceParent(TraceParent1)
+	start := time.Unix(10, 0)
+	span := badger.Span{
+		Name:         "GET /v1/users/:id/",
+		SpanContext:  sc,
+		ParentSpanID: sc.SpanID,
+		Start:        start,
+		End:          start.Add(time.Second),
+		Attributes:   map[string]interface{}{"http.response.status_code": 503},
+		Status:       503,
+	}
+
+	if err := exporter.Export(context.Background(), []badger.Span{span}); err != nil {
+		t.Fatalf("Test failed, unexpected error '%s'.", err)
+	}
+
+	if len(received.ResourceSpans) != 1 || len(received.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("Test failed, unexpected payload '%+v'.", received)
+	}
+
+	resource := received.ResourceSpans[0]
+
+	if attr := resource.Resource.Attributes; len(attr) != 1 || attr[0].Key != "service.name" || attr[0].Value.StringValue != "users" {
+		t.Errorf("Test failed, unexpected resource '%+v'.", attr)
+	}
+
+	s := resource.ScopeSpans[0].Spans[0]
+
+	if s.TraceID != TraceID1 || s.SpanID != SpanID1 || s.ParentSpanID != SpanID1 || s.Name != span.Name || s.Kind != 2 || s.StartTimeUnixNano != "10000000000" {
+		t.Errorf("Test failed, unexpected span '%+v'.", s)
+	}
+
+	if s.Status.Code != 2 || len(s.Attributes) != 1 || s.Attributes[0].Value.IntValue != "503" {
+		t.Errorf("Test failed, expected error status and int attribute got '%+v'.", s)
+	}
+}
+
+func TestOTLPExporterFailure(t *testing.T) {
+	var received OTLPRequest
+
+	collector := NewCollector(t, http.StatusServiceUnavailable, &received)
+	defer collector.Close()
+
+	exporter := badger.NewOTLPExporter(collector.URL+"/v1/traces", "users")
+	exporter.Headers = map[string]string{"Authorization": "token"}
+
+	sc, _ := badger.ParseTraceParent(TraceParent1)
+
+	if err := exporter.Export(context.Background(), []badger.Span{{SpanContext: sc}}); err == nil {
+		t.Error("Test failed, expected an error.")
+	}
+}
+
+func TestInMemoryExporterReset(t *testing.T) {
+	exporter := badger.NewInMemoryExporter()
+	exporter.Export(context.Background(), []badger.Span{{Name: "a"}, {Name: "b"}})
+
+	if spans := exporter.Spans(); len(spans) != 2 || spans[1].Name != "b" {
+		t.Errorf("Test failed, unexpected spans '%v'.", spans)
+	}
+
+	exporter.Reset()
+
+	if spans := exporter.Spans(); len(spans) != 0 {
+		t.Errorf("Test failed, expected no spans got %d.", len(spans))
+	}
+}
+
+func TestOTLPExporterTimeout(t *testing.T) {
+	release := make(chan struct{})
+	collector := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		<-release
+	}))
+	defer collector.Close()
+	defer close(release)
+
+	exporter := badger.NewOTLPExporter(collector.URL+"/v1/traces", "users")
+	exporter.Timeout = 50 * time.Millisecond
+
+	if err := exporter.Export(context.Background(), []badger.Span{{Name: "a"}}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Test failed, expected a deadline error got '%v'.", err)
+	}
+}
+
+type BlockingExporter struct {
+	release chan struct{}
+}
+
+func (e *BlockingExporter) Export(ctx context.Context, spans []badger.Span) error {
+	<-e.release
+	return nil
+}
+
+func TestBatchExporter(t *testing.T) {
+	exporter := badger.NewInMemoryExporter()
+	batch := badger.NewBatchExporter(exporter)
+	batch.BatchSize = 2
+
+	batch.Export(context.Background(), []badger.Span{{Name: "a"}, {Name: "b"}, {Name: "c"}})
+
+	if err := batch.Flush(context.Background()); err != nil {
+		t.Fatalf("Test failed, unexpected error '%s'.", err)
+	}
+
+	if spans := exporter.Spans(); len(spans) != 3 || spans[2].Name != "c" {
+		t.Errorf("Test failed, unexpected spans '%v'.", spans)
+	}
+
+	batch.Export(context.Background(), []badger.Span{{Name: "d"}})
+
+	if err := batch.Shutdown(context.Background()); err != nil || len(exporter.Spans()) != 4 {
+		t.Errorf("Test failed, expected queued spans to be exported on shutdown got '%v'.", err)
+	}
+
+	if err := batch.Export(context.Background(), []badger.Span{{Name: "e"}}); err != badger.ErrExporterShutdown {
+		t.Errorf("Test failed, expected ErrExporterShutdown got '%v'.", err)
+	}
+}
+
+func TestBatchExporterNeverBlocks(t *testing.T) {
+	exporter := &BlockingExporter{make(chan struct{})}
+	batch := badger.NewBatchExporter(exporter)
+	batch.QueueSize = 1
+	batch.BatchSize = 1
+
+	var err error
+
+	for i := 0; i < 3 && err == nil; i++ {
+		err = batch.Export(context.Background(), []badger.Span{{Name: "a"}})
+	}
+
+	if err != badger.ErrSpanQueueFull {
+		t.Errorf("Test failed, expected ErrSpanQueueFull got '%v'.", err)
+	}
+
+	close(exporter.release)
+	batch.Shutdown(context.Background())
+}
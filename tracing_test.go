@@ -0,0 +1,196 @@
+package badger_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hugoluchessi/badger"
+)
+
+const (
+	TraceParent1 = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	TraceID1     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	SpanID1      = "00f067aa0ba902b7"
+)
+
+func SpanContextHandlerFunc(found *badger.SpanContext) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		*found, _ = badger.SpanContextFromContext(req.Context())
+		res.WriteHeader(http.StatusAccepted)
+	}
+}
+
+func TestTracingContinuesTraceParent(t *testing.T) {
+	var sc badger.SpanContext
+
+	exporter := badger.NewInMemoryExporter()
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get("users/:id", SpanContextHandlerFunc(&sc))
+	router.Use(badger.NewTracing(exporter).Middleware)
+
+	ServeRequest(mux, GET, "/v1/users/42", nil, map[string]string{badger.TraceParentHeader: TraceParent1, badger.TraceStateHeader: "vendor=value"})
+
+	if sc.TraceID.String() != TraceID1 || sc.SpanID.String() == SpanID1 || !sc.IsSampled() || sc.TraceState != "vendor=value" {
+		t.Errorf("Test failed, unexpected span context '%s' '%s'.", sc.TraceParent(), sc.TraceState)
+	}
+
+	spans := exporter.Spans()
+
+	if len(spans) != 1 {
+		t.Fatalf("Test failed, expected 1 span got %d.", len(spans))
+	}
+
+	span := spans[0]
+
+	if span.Name != "GET /v1/users/:id/" || span.ParentSpanID.String() != SpanID1 || span.SpanContext != sc || span.Status != http.StatusAccepted {
+		t.Errorf("Test failed, unexpected span '%+v'.", span)
+	}
+
+	if span.Attributes["url.path"] != "/v1/users/42" || span.Attributes["http.route"] != "/v1/users/:id/" {
+		t.Errorf("Test failed, unexpected attributes '%v'.", span.Attributes)
+	}
+}
+
+func TestTracingStartsTrace(t *testing.T) {
+	var sc badger.SpanContext
+
+	exporter := badger.NewInMemoryExporter()
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get("users/:id", SpanContextHandlerFunc(&sc))
+	router.Use(badger.NewTracing(exporter).Middleware)
+
+	ServeRequest(mux, GET, "/v1/users/42", nil, map[string]string{badger.TraceParentHeader: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"})
+
+	if !sc.IsValid() || sc.TraceID.String() == TraceID1 || !sc.IsSampled() || sc.Remote {
+		t.Errorf("Test failed, expected a new trace got '%s'.", sc.TraceParent())
+	}
+
+	if spans := exporter.Spans(); len(spans) != 1 || spans[0].ParentSpanID.IsValid() {
+		t.Errorf("Test failed, expected a root span got '%v'.", spans)
+	}
+}
+
+func TestTracingNotSampled(t *testing.T) {
+	var sc badger.SpanContext
+
+	exporter := badger.NewInMemoryExporter()
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get("users/:id", SpanContextHandlerFunc(&sc))
+	router.Use(badger.NewTracing(exporter).Middleware)
+
+	ServeRequest(mux, GET, "/v1/users/42", nil, map[string]string{badger.TraceParentHeader: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"})
+
+	if sc.IsSampled() || sc.TraceID.String() != TraceID1 {
+		t.Errorf("Test failed, expected unsampled trace to continue got '%s'.", sc.TraceParent())
+	}
+
+	if spans := exporter.Spans(); len(spans) != 0 {
+		t.Errorf("Test failed, expected no exported span got %d.", len(spans))
+	}
+}
+
+func TestTracingB3(t *testing.T) {
+	tests := []struct {
+		headers map[string]string
+		traceid string
+		sampled bool
+	}{
+		{map[string]string{badger.B3Header: TraceID1 + "-" + SpanID1 + "-1"}, TraceID1, true},
+		{map[string]string{badger.B3Header: "a3ce929d0e0e4736-" + SpanID1 + "-0"}, "0000000000000000a3ce929d0e0e4736", false},
+		{map[string]string{badger.B3TraceIDHeader: TraceID1, badger.B3SpanIDHeader: SpanID1, badger.B3SampledHeader: "1"}, TraceID1, true},
+	}
+
+	for _, test := range tests {
+		var sc badger.SpanContext
+
+		tracing := badger.NewTracing(badger.NewInMemoryExporter())
+		tracing.B3 = true
+		mux := badger.NewMux()
+		router := mux.AddRouter(RouterBasePath1)
+		router.Get("users/:id", SpanContextHandlerFunc(&sc))
+		router.Use(tracing.Middleware)
+
+		ServeRequest(mux, GET, "/v1/users/42", nil, test.headers)
+
+		if sc.TraceID.String() != test.traceid || sc.IsSampled() != test.sampled {
+			t.Errorf("Test failed, headers '%v' gave '%s'.", test.headers, sc.TraceParent())
+		}
+	}
+}
+
+func TestTracingIgnoresB3ByDefault(t *testing.T) {
+	var sc badger.SpanContext
+
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Get("users/:id", SpanContextHandlerFunc(&sc))
+	router.Use(badger.NewTracing(nil).Middleware)
+	ServeRequest(mux, GET, "/v1/users/42", nil, map[string]string{badger.B3Header: TraceID1 + "-" + SpanID1 + "-1"})
+
+	if sc.TraceID.String() == TraceID1 {
+		t.Error("Test failed, expected b3 header to be ignored.")
+	}
+}
+
+func TestParseTraceParent(t *testing.T) {
+	tests := map[string]bool{
+		TraceParent1: true,
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future": true,
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra":  false,
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01":        false,
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01":        false,
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01":        false,
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7":           false,
+		"": false,
+	}
+
+	for value, expected := range tests {
+		if _, ok := badger.ParseTraceParent(value); ok != expected {
+			t.Errorf("Test failed, expected '%s' valid to be %t.", value, expected)
+		}
+	}
+}
+
+func TestTraceTransport(t *testing.T) {
+	var sent *http.Request
+	var sc badger.SpanContext
+
+	exporter := badger.NewInMemoryExporter()
+	client := &http.Client{Transport: &badger.TraceTransport{
+		Base: RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			sent = req
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+		}),
+		B3: true,
+	}}
+
+	mux := badger.NewMux()
+	router := mux.AddRouter(RouterBasePath1)
+	router.Use(badger.NewTracing(exporter).Middleware)
+	router.Get("call", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		sc, _ = badger.SpanContextFromContext(req.Context())
+		out, _ := http.NewRequestWithContext(req.Context(), GET, "http://upstream/", nil)
+		client.Do(out)
+	}))
+
+	ServeRequest(mux, GET, "/v1/call", nil, map[string]string{badger.TraceParentHeader: TraceParent1, badger.TraceStateHeader: "vendor=value"})
+
+	if sent == nil {
+		t.Fatal("Test failed, expected the request to be sent.")
+	}
+
+	if tp := sent.Header.Get(badger.TraceParentHeader); tp != sc.TraceParent() {
+		t.Errorf("Test failed, expected traceparent '%s' got '%s'.", sc.TraceParent(), tp)
+	}
+
+	if ts := sent.Header.Get(badger.TraceStateHeader); ts != "vendor=value" {
+		t.Errorf("Test failed, expected tracestate 'vendor=value' got '%s'.", ts)
+	}
+
+	if b3 := sent.Header.Get(badger.B3Header); b3 != TraceID1+"-"+sc.SpanID.String()+"-1" {
+		t.Errorf("Test failed, unexpected b3 header '%s'.", b3)
+	}
+}
@@ -7,6 +7,28 @@ import (
 
 const notFoundErrorMessageFormat = "Key '%s' could not be found."
 
+const invalidErrorMessageFormat = "Key '%s' has an invalid value: %s"
+
+// ParamError is returned when a key is not found or its value can not be
+// converted to the requested type
+type ParamError struct {
+	Key string
+	// Err is the conversion error, nil when the key was not found
+	Err error
+}
+
+func (e *ParamError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf(notFoundErrorMessageFormat, e.Key)
+	}
+
+	return fmt.Sprintf(invalidErrorMessageFormat, e.Key, e.Err.Error())
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
 // TypedParams is a helper struct fo handling param objects, has helper
 // functions to retrieve typed data
 type TypedParams struct {
@@ -25,7 +47,7 @@ func (t TypedParams) GetString(key string) (string, error) {
 		return val, nil
 	}
 
-	return "", fmt.Errorf(notFoundErrorMessageFormat, key)
+	return "", &ParamError{key, nil}
 }
 
 // GetInt returns an integer value for the given key, returns 0 and an error
@@ -40,5 +62,9 @@ func (t TypedParams) GetInt(key string) (int, error) {
 
 	ivalue, err := strconv.Atoi(value)
 
-	return ivalue, err
+	if err != nil {
+		return 0, &ParamError{key, err}
+	}
+
+	return ivalue, nil
 }
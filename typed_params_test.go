@@ -1,6 +1,7 @@
 package badger_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/hugoluchessi/badger"
@@ -81,3 +82,16 @@ func TestGetInexistingIntKey(t *testing.T) {
 		t.Errorf("Test failed, expected value to be '%d' got '%d'.", 0, rvalue)
 	}
 }
+
+func TestGetIntInvalidValueParamError(t *testing.T) {
+	key := "map"
+
+	typedmap := badger.CreateTypedParams(map[string]string{key: "abc"})
+
+	_, err := typedmap.GetInt(key)
+
+	var paramerror *badger.ParamError
+	if !errors.As(err, &paramerror) || paramerror.Key != key || paramerror.Err == nil {
+		t.Errorf("Test failed, expected a ParamError for key '%s' got '%v'.", key, err)
+	}
+}